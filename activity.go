@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ActivityScore is how well a day suits an activity, from 0 to 10.
+type ActivityScore struct {
+	Activity string
+	Score    int
+}
+
+// band rates value 1 inside low–high, fading to 0 over fade either side.
+func band(value, low, high, fade float64) float64 {
+	switch {
+	case value < low:
+		return math.Max(0, 1-(low-value)/fade)
+	case value > high:
+		return math.Max(0, 1-(value-high)/fade)
+	}
+	return 1
+}
+
+// dryness rates a day's rain from 1, dry, to 0, a washout: 5 mm or a
+// certain chance of rain is enough to spoil a day outside.
+func dryness(day DayOutlook) float64 {
+	dry := 1 - math.Min(day.PrecipMM/5, 1)
+	if day.RainChance >= 0 {
+		dry = math.Min(dry, 1-float64(day.RainChance)/100)
+	}
+	return dry
+}
+
+// activityScores rates a day for the beach, for hiking and for staying in
+// to work, best first. Unknown wind or UV counts as middling.
+func activityScores(day DayOutlook) []ActivityScore {
+	dry := dryness(day)
+	calm, breezy, sunny, safeSun := 0.5, 0.5, 0.5, 0.5
+	if day.WindKmh >= 0 {
+		// Sand blows about over 20 km/h; ridges get unpleasant over 30
+		calm = band(day.WindKmh, 0, 20, 25)
+		breezy = band(day.WindKmh, 0, 30, 30)
+	}
+	if day.UVIndex >= 0 {
+		// A beach wants some sun; a long walk without shade wants less
+		sunny = math.Min(day.UVIndex/5, 1)
+		safeSun = band(day.UVIndex, 0, 7, 5)
+	}
+
+	beach := 0.4*band(day.MaxC, 26, 33, 8) + 0.3*dry + 0.2*calm + 0.1*sunny
+	hiking := 0.35*band(day.MaxC, 12, 24, 10) + 0.35*dry + 0.2*breezy + 0.1*safeSun
+	// Work indoors is the pick when the weather is no good for either
+	indoor := 1 - math.Max(beach, hiking)
+
+	scores := []ActivityScore{
+		{"Beach day", int(math.Round(beach * 10))},
+		{"Hiking day", int(math.Round(hiking * 10))},
+		{"Indoor work day", int(math.Round(indoor * 10))},
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Score > scores[j].Score })
+	return scores
+}
+
+// printActivityLine prints the day's best activity with its score and the
+// others beside it, e.g. "Beach day 8/10 (hiking 6, indoor work 2)".
+func printActivityLine(day *DayOutlook) {
+	scores := activityScores(*day)
+	others := make([]string, 0, len(scores)-1)
+	for _, score := range scores[1:] {
+		name := strings.ToLower(strings.TrimSuffix(score.Activity, " day"))
+		others = append(others, fmt.Sprintf("%s %d", name, score.Score))
+	}
+	fmt.Printf("  %s %s %s\n", padRight(iconInfo("Verdict"), 14),
+		colorGreen(fmt.Sprintf("%s %d/10", scores[0].Activity, scores[0].Score)),
+		colorCyan("("+strings.Join(others, ", ")+")"))
+}
@@ -0,0 +1,94 @@
+package main
+
+import "strings"
+
+// Airline is an entry in the offline baggage dataset. Prices are what the
+// airline typically charges and change with route, date and fare.
+type Airline struct {
+	Code    string // IATA code
+	Name    string
+	Aliases []string
+	Cabin   string // included cabin allowance
+	Checked string // included checked allowance in economy
+	// Currency is the currency Fees and ExcessPerKg are quoted in
+	Currency string
+	// Fees are the usual prices to add checked bags when booking online
+	Fees []BaggageFee
+	// ExcessPerKg is the usual charge at the airport for each kg over
+	ExcessPerKg float64
+}
+
+// BaggageFee is the price of a checked bag add-on on domestic and
+// international flights.
+type BaggageFee struct {
+	Label         string
+	Domestic      float64
+	International float64
+}
+
+// airlines lists carriers commonly flown between nomad hubs, low-cost
+// carriers first as their fees matter most.
+var airlines = []Airline{
+	{"AK", "AirAsia", []string{"airasia", "air asia", "FD", "QZ", "Z2", "D7"},
+		"7 kg, one bag and one small item", "None", "MYR",
+		[]BaggageFee{{"20 kg", 55, 95}, {"25 kg", 70, 120}, {"30 kg", 90, 155}, {"40 kg", 150, 245}}, 40},
+	{"VJ", "VietJet Air", []string{"vietjet"},
+		"7 kg, one bag and one small item", "None", "VND",
+		[]BaggageFee{{"20 kg", 220000, 450000}, {"30 kg", 330000, 620000}, {"40 kg", 440000, 800000}}, 120000},
+	{"5J", "Cebu Pacific", []string{"cebu", "cebu pacific air"},
+		"7 kg, one bag and one small item", "None", "PHP",
+		[]BaggageFee{{"20 kg", 650, 1500}, {"32 kg", 1000, 2200}}, 500},
+	{"TR", "Scoot", []string{"scoot"},
+		"10 kg, two pieces", "None", "SGD",
+		[]BaggageFee{{"20 kg", 30, 45}, {"30 kg", 45, 70}}, 35},
+	{"JQ", "Jetstar", []string{"jetstar", "3K", "GK"},
+		"7 kg, two pieces", "None", "AUD",
+		[]BaggageFee{{"15 kg", 30, 45}, {"20 kg", 40, 60}, {"30 kg", 65, 90}}, 25},
+	{"FR", "Ryanair", []string{"ryanair"},
+		"One small bag (40x20x25 cm); 10 kg cabin bag with Priority", "None", "EUR",
+		[]BaggageFee{{"10 kg Priority", 20, 20}, {"20 kg", 35, 35}}, 13},
+	{"U2", "easyJet", []string{"easyjet", "easy jet", "EC", "DS"},
+		"One under-seat bag (45x36x20 cm)", "None", "GBP",
+		[]BaggageFee{{"Large cabin bag", 15, 15}, {"15 kg", 25, 25}, {"23 kg", 32, 32}}, 14},
+	{"W6", "Wizz Air", []string{"wizz", "wizzair", "wizz air"},
+		"One small bag (40x30x20 cm)", "None", "EUR",
+		[]BaggageFee{{"10 kg cabin", 18, 18}, {"20 kg", 30, 30}, {"32 kg", 45, 45}}, 15},
+	{"TG", "Thai Airways", []string{"thai", "thai airways"},
+		"7 kg plus a personal item", "20 kg domestic, 25-30 kg international", "THB",
+		[]BaggageFee{{"+5 kg", 500, 1200}, {"+10 kg", 900, 2200}}, 350},
+	{"SQ", "Singapore Airlines", []string{"singapore", "singapore airlines"},
+		"7 kg plus a personal item", "25-30 kg", "SGD",
+		[]BaggageFee{{"+5 kg", 40, 40}, {"+10 kg", 75, 75}}, 50},
+	{"TK", "Turkish Airlines", []string{"turkish", "thy"},
+		"8 kg plus a personal item", "15 kg domestic, 20-30 kg international", "EUR",
+		[]BaggageFee{{"+5 kg", 10, 35}, {"+10 kg", 18, 60}}, 15},
+	{"EK", "Emirates", []string{"emirates"},
+		"7 kg plus a personal item", "25-35 kg", "USD",
+		[]BaggageFee{{"+5 kg", 65, 65}, {"+10 kg", 120, 120}}, 25},
+}
+
+// findAirline looks up an airline by IATA code, name or alias.
+func findAirline(name string) *Airline {
+	name = strings.TrimSpace(name)
+	for i := range airlines {
+		airline := &airlines[i]
+		if strings.EqualFold(airline.Code, name) || strings.EqualFold(airline.Name, name) {
+			return airline
+		}
+		for _, alias := range airline.Aliases {
+			if strings.EqualFold(alias, name) {
+				return airline
+			}
+		}
+	}
+	return nil
+}
+
+// airlineNames returns the names of every airline in the dataset.
+func airlineNames() []string {
+	names := make([]string, len(airlines))
+	for i, airline := range airlines {
+		names[i] = airline.Name
+	}
+	return names
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// handleAirport shows local time, distance, transit and weather for an
+// airport, e.g. `nomad airport BKK`.
+func handleAirport(args []string) {
+	if len(args) < 1 {
+		printError("Usage: nomad airport <IATA code or city>\n")
+		printInfo("Example: nomad airport BKK\n")
+		os.Exit(1)
+	}
+	query := strings.Join(args, " ")
+
+	airport := findAirport(query)
+	if airport == nil {
+		airport = airportForCity(query)
+	}
+
+	loc, err := time.LoadLocation(airport.Timezone)
+	if err != nil {
+		printError("Error loading timezone: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The airport's weather and our own IP-based location are independent
+	var airportWeather, localWeather *WttrResponse
+	var airportErr, localErr error
+	WithSpinner("Fetching airport weather...", func() error {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			airportWeather, airportErr = wttr.Forecast(fmt.Sprintf("%.4f,%.4f", airport.Lat, airport.Lon))
+		}()
+		go func() {
+			defer wg.Done()
+			localWeather, localErr = wttr.Forecast("")
+		}()
+		wg.Wait()
+		return nil
+	})
+
+	fmt.Println()
+	printTitle("%s %s (%s)\n", iconLocation(""), airport.Name, airport.Code)
+	fmt.Printf("  %s %s, %s\n", padRight(iconLocation("City"), 14), airport.City, airport.Country)
+	fmt.Printf("  %s %s %s\n", padRight(iconTime("Local time"), 14),
+		colorYellow(time.Now().In(loc).Format("Mon 3:04 PM")), colorCyan("("+airport.Timezone+")"))
+
+	if localErr == nil {
+		if area, ok := localWeather.Area(); ok {
+			rememberCountry(area.Country.String())
+			if lat, lon, ok := area.Coordinates(); ok {
+				fmt.Printf("  %s %s %s\n", padRight(iconNetwork("Distance"), 14),
+					colorYellow(formatDistance(distanceKm(lat, lon, airport.Lat, airport.Lon))),
+					colorCyan("from "+area.AreaName.String()))
+			}
+		}
+	}
+
+	if airportErr != nil {
+		printWarning("  Weather unavailable: %v\n", airportErr)
+	} else if current, ok := airportWeather.Current(); ok {
+		details := []string{colorCyan(current.WeatherDesc.String())}
+		if temp, ok := current.TempC.Float(); ok {
+			details = append(details, colorYellow(formatTemp(temp)))
+		}
+		if wind, ok := current.WindKmph.Float(); ok {
+			details = append(details, "wind "+formatKmh(wind))
+		}
+		fmt.Printf("  %s %s\n", padRight(iconWeather("Weather"), 14), strings.Join(details, ", "))
+	}
+
+	if len(airport.Transit) > 0 {
+		fmt.Println()
+		printTitle("%s Getting to %s\n", iconInfo(""), airport.City)
+		for _, option := range airport.Transit {
+			fmt.Printf("  • %s\n", option)
+		}
+		fmt.Println()
+		addSource(Source{What: "Transit", From: builtInData, Confidence: confidenceMedium,
+			Check: "typical routes and times, so check for changes before you land"})
+	}
+	addSource(Source{What: "Airport and time zone", From: builtInData, Confidence: confidenceHigh})
+	if airportErr == nil {
+		addSource(Source{What: "Weather", From: "wttr.in", Confidence: confidenceHigh})
+	}
+	printSources()
+}
+
+// airportForCity finds the airport serving a city, offering a choice when
+// there are several.
+func airportForCity(city string) *Airport {
+	var matches []*Airport
+	var labels []string
+	for i := range airports {
+		if strings.EqualFold(airports[i].City, city) {
+			matches = append(matches, &airports[i])
+			labels = append(labels, airports[i].Code+" - "+airports[i].Name)
+		}
+	}
+
+	switch {
+	case len(matches) == 1:
+		return matches[0]
+	case len(matches) > 1 && isInteractive():
+		choice := mustPick(Pick("Airport", "", labels))
+		code, _, _ := strings.Cut(choice, " - ")
+		return findAirport(code)
+	case len(matches) > 1:
+		printError("Error: %s has several airports: %s\n", city, strings.Join(labels, ", "))
+	default:
+		printError("Error: Unknown airport '%s'\n", city)
+	}
+	os.Exit(1)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import "strings"
+
+// Airport is an entry in the offline airport dataset.
+type Airport struct {
+	Code     string // IATA code
+	Name     string
+	City     string
+	Country  string
+	Lat      float64
+	Lon      float64
+	Timezone string
+	// Transit lists the usual ways into the city center
+	Transit []string
+}
+
+// airports lists the main international airports serving nomad hubs.
+var airports = []Airport{
+	{"AMS", "Amsterdam Schiphol", "Amsterdam", "Netherlands", 52.3105, 4.7683, "Europe/Amsterdam",
+		[]string{"NS train to Centraal, 15 min", "Bus 397 to Museumplein, 30 min"}},
+	{"ATH", "Athens International", "Athens", "Greece", 37.9364, 23.9445, "Europe/Athens",
+		[]string{"Metro line 3 to Syntagma, 40 min", "X95 express bus to Syntagma, 60 min"}},
+	{"BCN", "Barcelona El Prat", "Barcelona", "Spain", 41.2974, 2.0833, "Europe/Madrid",
+		[]string{"Aerobús to Plaça Catalunya, 35 min", "Metro L9 Sud to Zona Universitària, 30 min"}},
+	{"BER", "Berlin Brandenburg", "Berlin", "Germany", 52.3667, 13.5033, "Europe/Berlin",
+		[]string{"FEX airport express to Hauptbahnhof, 30 min", "S-Bahn S9 to Alexanderplatz, 45 min"}},
+	{"BKK", "Suvarnabhumi", "Bangkok", "Thailand", 13.6900, 100.7501, "Asia/Bangkok",
+		[]string{"Airport Rail Link to Phaya Thai, 30 min", "Metered taxi, 45-60 min"}},
+	{"BOG", "El Dorado", "Bogota", "Colombia", 4.7016, -74.1469, "America/Bogota",
+		[]string{"TransMilenio feeder bus, 60 min", "Taxi, 30-45 min"}},
+	{"BUD", "Budapest Ferenc Liszt", "Budapest", "Hungary", 47.4369, 19.2556, "Europe/Budapest",
+		[]string{"Bus 100E to Deák Ferenc tér, 40 min"}},
+	{"CNX", "Chiang Mai International", "Chiang Mai", "Thailand", 18.7668, 98.9626, "Asia/Bangkok",
+		[]string{"Grab or airport taxi to the Old City, 15 min"}},
+	{"CPT", "Cape Town International", "Cape Town", "South Africa", -33.9715, 18.6021, "Africa/Johannesburg",
+		[]string{"MyCiTi A01 bus to Civic Centre, 30 min", "Uber, 25 min"}},
+	{"DMK", "Don Mueang", "Bangkok", "Thailand", 13.9126, 100.6068, "Asia/Bangkok",
+		[]string{"SRT Red Line to Bang Sue, 15 min", "A1/A2 bus to Mo Chit BTS, 30 min"}},
+	{"DPS", "Ngurah Rai", "Bali", "Indonesia", -8.7482, 115.1672, "Asia/Makassar",
+		[]string{"Pre-paid taxi or Grab to Canggu, 45-90 min", "Kura-Kura bus to Ubud, 90 min"}},
+	{"DXB", "Dubai International", "Dubai", "United Arab Emirates", 25.2532, 55.3657, "Asia/Dubai",
+		[]string{"Metro Red Line to Downtown, 25 min", "Taxi, 20 min"}},
+	{"HAN", "Noi Bai", "Hanoi", "Vietnam", 21.2212, 105.8072, "Asia/Bangkok",
+		[]string{"Bus 86 to the Old Quarter, 60 min", "Grab, 45 min"}},
+	{"HKG", "Hong Kong International", "Hong Kong", "China", 22.3080, 113.9185, "Asia/Hong_Kong",
+		[]string{"Airport Express to Central, 24 min", "A21 bus to Mong Kok, 60 min"}},
+	{"ICN", "Incheon International", "Seoul", "South Korea", 37.4602, 126.4407, "Asia/Seoul",
+		[]string{"AREX express to Seoul Station, 43 min", "Airport limousine bus, 70 min"}},
+	{"IST", "Istanbul Airport", "Istanbul", "Turkey", 41.2753, 28.7519, "Europe/Istanbul",
+		[]string{"Metro M11 to Gayrettepe, 35 min", "Havaist bus to Taksim, 75 min"}},
+	{"JFK", "John F. Kennedy International", "New York", "United States", 40.6413, -73.7781, "America/New_York",
+		[]string{"AirTrain and LIRR to Penn Station, 50 min", "AirTrain and subway E, 70 min"}},
+	{"KUL", "Kuala Lumpur International", "Kuala Lumpur", "Malaysia", 2.7456, 101.7099, "Asia/Kuala_Lumpur",
+		[]string{"KLIA Ekspres to KL Sentral, 28 min", "Airport coach to KL Sentral, 60 min"}},
+	{"LHR", "London Heathrow", "London", "United Kingdom", 51.4700, -0.4543, "Europe/London",
+		[]string{"Heathrow Express to Paddington, 15 min", "Elizabeth line to central London, 35 min", "Piccadilly line, 50 min"}},
+	{"LIS", "Lisbon Humberto Delgado", "Lisbon", "Portugal", 38.7742, -9.1342, "Europe/Lisbon",
+		[]string{"Metro red line to Saldanha, 20 min", "Aerobus to Cais do Sodré, 30 min"}},
+	{"MAD", "Adolfo Suárez Madrid-Barajas", "Madrid", "Spain", 40.4983, -3.5676, "Europe/Madrid",
+		[]string{"Cercanías C1 to Atocha, 25 min", "Exprés Aeropuerto bus to Atocha, 40 min"}},
+	{"MDE", "José María Córdova", "Medellin", "Colombia", 6.1645, -75.4231, "America/Bogota",
+		[]string{"Airport bus to San Diego, 60 min", "Taxi to El Poblado, 45 min"}},
+	{"MEX", "Mexico City International", "Mexico City", "Mexico", 19.4361, -99.0719, "America/Mexico_City",
+		[]string{"Metrobús line 4 to the Centro Histórico, 40 min", "Authorized taxi to Roma/Condesa, 30 min"}},
+	{"MEL", "Melbourne Tullamarine", "Melbourne", "Australia", -37.6690, 144.8410, "Australia/Melbourne",
+		[]string{"SkyBus to Southern Cross, 30 min"}},
+	{"NRT", "Narita International", "Tokyo", "Japan", 35.7720, 140.3929, "Asia/Tokyo",
+		[]string{"Narita Express to Tokyo Station, 55 min", "Keisei Skyliner to Ueno, 41 min"}},
+	{"HND", "Haneda", "Tokyo", "Japan", 35.5494, 139.7798, "Asia/Tokyo",
+		[]string{"Keikyu line to Shinagawa, 15 min", "Tokyo Monorail to Hamamatsucho, 20 min"}},
+	{"OPO", "Porto Francisco Sá Carneiro", "Porto", "Portugal", 41.2481, -8.6814, "Europe/Lisbon",
+		[]string{"Metro line E to Trindade, 30 min"}},
+	{"PRG", "Václav Havel Airport Prague", "Prague", "Czech Republic", 50.1008, 14.2600, "Europe/Prague",
+		[]string{"Bus 119 to Nádraží Veleslavín then metro A, 40 min", "Airport Express bus to the main station, 35 min"}},
+	{"SFO", "San Francisco International", "San Francisco", "United States", 37.6213, -122.3790, "America/Los_Angeles",
+		[]string{"BART to Powell Street, 30 min"}},
+	{"SGN", "Tan Son Nhat", "Ho Chi Minh City", "Vietnam", 10.8188, 106.6519, "Asia/Ho_Chi_Minh",
+		[]string{"Bus 109 to Ben Thanh, 45 min", "Grab to District 1, 30 min"}},
+	{"SIN", "Singapore Changi", "Singapore", "Singapore", 1.3644, 103.9915, "Asia/Singapore",
+		[]string{"MRT East-West line to City Hall, 35 min", "Taxi, 25 min"}},
+	{"SYD", "Sydney Kingsford Smith", "Sydney", "Australia", -33.9399, 151.1753, "Australia/Sydney",
+		[]string{"Airport Link train to Central, 15 min"}},
+	{"TBS", "Tbilisi International", "Tbilisi", "Georgia", 41.6692, 44.9547, "Asia/Tbilisi",
+		[]string{"Bus 337 to the central station, 40 min", "Bolt, 25 min"}},
+	{"TPE", "Taiwan Taoyuan International", "Taipei", "Taiwan", 25.0797, 121.2342, "Asia/Taipei",
+		[]string{"Taoyuan Airport MRT express to Taipei Main Station, 35 min"}},
+	{"YVR", "Vancouver International", "Vancouver", "Canada", 49.1967, -123.1815, "America/Vancouver",
+		[]string{"Canada Line SkyTrain to Waterfront, 26 min"}},
+}
+
+// findAirport looks up an airport by IATA code.
+func findAirport(code string) *Airport {
+	for i := range airports {
+		if strings.EqualFold(airports[i].Code, code) {
+			return &airports[i]
+		}
+	}
+	return nil
+}
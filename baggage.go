@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// handleBaggage shows an airline's baggage allowances and typical fees in
+// the home currency, e.g. `nomad baggage airasia --route BKK-DPS`.
+func handleBaggage(args []string) {
+	args, route, _ := popFlagValue(args, "--route")
+	args, toCurrency, _ := popFlagValue(args, "--to")
+
+	if len(args) < 1 {
+		printError("Usage: nomad baggage <airline> [--route FROM-TO] [--to currency]\n")
+		printInfo("Example: nomad baggage airasia --route BKK-DPS\n")
+		os.Exit(1)
+	}
+
+	airline := findAirline(strings.Join(args, " "))
+	if airline == nil {
+		printError("Error: Unknown airline '%s'\n", strings.Join(args, " "))
+		printInfo("Known airlines: %s\n", strings.Join(airlineNames(), ", "))
+		os.Exit(1)
+	}
+
+	// Without a route both fare columns are shown
+	showDomestic, showInternational := true, true
+	var routeLabel string
+	if route != "" {
+		fromCode, toCode, ok := strings.Cut(strings.ToUpper(route), "-")
+		if !ok || len(fromCode) != 3 || len(toCode) != 3 {
+			printError("Error: Invalid route '%s' (expected FROM-TO, e.g. BKK-DPS)\n", route)
+			os.Exit(1)
+		}
+
+		routeLabel = fromCode + " → " + toCode
+		from, to := findAirport(fromCode), findAirport(toCode)
+		if from != nil && to != nil {
+			domestic := from.Country == to.Country
+			showDomestic, showInternational = domestic, !domestic
+			if domestic {
+				routeLabel += " (domestic)"
+			} else {
+				routeLabel += " (international)"
+			}
+		}
+	}
+
+	if toCurrency == "" {
+		toCurrency = config.Get("home_currency")
+	}
+	toCurrency = strings.ToUpper(toCurrency)
+	if toCurrency == "" {
+		toCurrency = airline.Currency
+	}
+
+	rate := 1.0
+	if toCurrency != airline.Currency {
+		snapshotFallback = true
+		err := WithSpinner("Fetching exchange rates...", func() error {
+			var fetchErr error
+			rate, fetchErr = getExchangeRate(airline.Currency, toCurrency)
+			return fetchErr
+		})
+		if err != nil {
+			printError("Error getting exchange rate: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	formatFee := func(amount float64) string {
+		text := formatFeeAmount(amount) + " " + airline.Currency
+		if toCurrency != airline.Currency {
+			text += " " + colorCyan(fmt.Sprintf("(%s %s)", formatFeeAmount(amount*rate), toCurrency))
+		}
+		return text
+	}
+
+	fmt.Println()
+	printTitle("%s %s (%s) baggage\n", iconInfo(""), airline.Name, airline.Code)
+	if routeLabel != "" {
+		fmt.Printf("  %s %s\n", padRight(iconLocation("Route"), 14), routeLabel)
+	}
+	fmt.Printf("  %s %s\n", padRight(iconSuccess("Cabin"), 14), airline.Cabin)
+	fmt.Printf("  %s %s\n", padRight(iconSuccess("Checked"), 14), airline.Checked)
+	fmt.Println()
+
+	if len(airline.Fees) > 0 {
+		headers := []string{"Add-on"}
+		if showDomestic {
+			headers = append(headers, "Domestic")
+		}
+		if showInternational {
+			headers = append(headers, "International")
+		}
+		table := NewTable(headers...)
+		for _, fee := range airline.Fees {
+			row := []string{fee.Label}
+			if showDomestic {
+				row = append(row, colorGreen(formatFee(fee.Domestic)))
+			}
+			if showInternational {
+				row = append(row, colorGreen(formatFee(fee.International)))
+			}
+			table.AddRow(row...)
+		}
+		table.Print()
+		fmt.Println()
+	}
+
+	if airline.ExcessPerKg > 0 {
+		fmt.Printf("  %s %s per kg\n", padRight(iconInfo("At airport"), 14), colorYellow(formatFee(airline.ExcessPerKg)))
+	}
+	printSnapshotNotice()
+	addSource(Source{What: "Baggage fees", From: builtInData, Confidence: confidenceMedium,
+		Check: fmt.Sprintf("typical online prices that change with route, date and fare, so check with %s before flying", airline.Name)})
+	if toCurrency != airline.Currency {
+		addRateSource()
+	}
+	printSources()
+}
+
+// formatFeeAmount rounds large fees to whole units with thousands
+// separators, since airlines never charge 219,999.87 VND.
+func formatFeeAmount(amount float64) string {
+	switch {
+	case amount >= 100:
+		return formatGrouped(amount)
+	case amount == math.Trunc(amount):
+		return fmt.Sprintf("%.0f", amount)
+	default:
+		return fmt.Sprintf("%.2f", amount)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BasketWeight is one currency's share of a basket.
+type BasketWeight struct {
+	Currency string
+	Weight   float64
+}
+
+// parseBasketWeights reads "eur:0.4,thb:0.3,vnd:0.3", scaling the weights
+// to add up to 1.
+func parseBasketWeights(s string) ([]BasketWeight, error) {
+	var weights []BasketWeight
+	var total float64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		code, weightText, ok := strings.Cut(part, ":")
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightText), 64)
+		if !ok || err != nil || weight <= 0 {
+			return nil, fmt.Errorf("invalid basket weight '%s' (use currency:weight, e.g. eur:0.4)", part)
+		}
+		currency, err := resolveCurrency(strings.TrimSpace(code))
+		if err != nil {
+			return nil, err
+		}
+		weights = append(weights, BasketWeight{currency, weight})
+		total += weight
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("no basket weights given")
+	}
+
+	for i := range weights {
+		weights[i].Weight /= total
+	}
+	return weights, nil
+}
+
+// basketIndex weighs each currency's rate against its first value, so 100
+// is where the period started and above 100 means the base buys more of
+// the basket. Only days with a rate for every currency count.
+func basketIndex(weights []BasketWeight, series map[string][]RatePoint) []RatePoint {
+	byDay := make(map[time.Time]map[string]float64)
+	for currency, points := range series {
+		for _, point := range points {
+			if byDay[point.Date] == nil {
+				byDay[point.Date] = make(map[string]float64)
+			}
+			byDay[point.Date][currency] = point.Rate
+		}
+	}
+
+	var days []time.Time
+	for day, rates := range byDay {
+		if len(rates) == len(weights) {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	if len(days) == 0 {
+		return nil
+	}
+
+	first := byDay[days[0]]
+	index := make([]RatePoint, len(days))
+	for i, day := range days {
+		var value float64
+		for _, w := range weights {
+			value += w.Weight * byDay[day][w.Currency] / first[w.Currency]
+		}
+		index[i] = RatePoint{Date: day, Rate: value * 100}
+	}
+	return index
+}
+
+// handleBasket implements `nomad cv basket [--base usd] [--weights
+// eur:0.4,thb:0.3] [--days 90]`, tracking the home currency against the
+// places you plan to spend it.
+func handleBasket(args []string) {
+	args, base, _ := popFlagValue(args, "--base")
+	args, weightsText, _ := popFlagValue(args, "--weights")
+	args, daysStr, _ := popFlagValue(args, "--days")
+
+	if weightsText == "" {
+		weightsText = config.Get("basket")
+	}
+	if weightsText == "" {
+		printError("Usage: nomad cv basket [--base usd] --weights eur:0.4,thb:0.3,vnd:0.3 [--days 90]\n")
+		printInfo("Tip: set basket in your config to skip --weights\n")
+		os.Exit(1)
+	}
+	weights, err := parseBasketWeights(weightsText)
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if base == "" {
+		base = config.Get("home_currency")
+	}
+	if base == "" {
+		base = "USD"
+	}
+	base = mustResolveCurrency(base)
+	for _, w := range weights {
+		if w.Currency == base {
+			printError("Error: %s is the base currency, leave it out of the basket\n", base)
+			os.Exit(1)
+		}
+	}
+
+	days := 90
+	if daysStr != "" {
+		if days, err = strconv.Atoi(daysStr); err != nil || days < 2 || days > 3650 {
+			printError("Error: Invalid number of days '%s' (use 2 to 3650)\n", daysStr)
+			os.Exit(1)
+		}
+	}
+
+	// Currencies without history are dropped and the rest reweighted, so
+	// one exotic pick doesn't sink the whole basket
+	series := make(map[string][]RatePoint)
+	var skipped []string
+	WithSpinner("Fetching rate history...", func() error {
+		for _, w := range weights {
+			points, err := getRateHistory(base, w.Currency, days)
+			if err != nil || len(points) < 2 {
+				logVerbose("no history for %s/%s: %v", base, w.Currency, err)
+				skipped = append(skipped, w.Currency)
+				continue
+			}
+			series[w.Currency] = points
+		}
+		return nil
+	})
+
+	var covered []BasketWeight
+	var total float64
+	for _, w := range weights {
+		if _, ok := series[w.Currency]; ok {
+			covered = append(covered, w)
+			total += w.Weight
+		}
+	}
+	if len(covered) == 0 {
+		printError("Error: No rate history for any basket currency against %s (only major currencies are covered)\n", base)
+		os.Exit(1)
+	}
+	for i := range covered {
+		covered[i].Weight /= total
+	}
+	weights = covered
+
+	index := basketIndex(weights, series)
+	if len(index) < 2 {
+		printError("Error: Not enough overlapping rate history for the basket\n")
+		os.Exit(1)
+	}
+
+	values := make([]float64, len(index))
+	for i, point := range index {
+		values[i] = point.Rate
+	}
+	first, last := index[0], index[len(index)-1]
+	change := last.Rate - first.Rate
+
+	fmt.Println()
+	printTitle("%s %s against your basket, last %d days\n", iconCurrency(""), base, days)
+	fmt.Print(NewChart(values).SetHeight(chartHeight).SetLabels(first.Date.Format("Jan 2"), last.Date.Format("Jan 2")).
+		SetFormat(func(v float64) string { return fmt.Sprintf("%.1f", v) }).Render())
+	fmt.Println()
+
+	table := NewTable("Currency", "Weight", "Change")
+	for _, w := range weights {
+		points := series[w.Currency]
+		move := (points[len(points)-1].Rate - points[0].Rate) / points[0].Rate * 100
+		table.AddRow(w.Currency, fmt.Sprintf("%.0f%%", w.Weight*100), colorForChange(move)(fmt.Sprintf("%+.2f%%", move)))
+	}
+	table.Print()
+
+	fmt.Println()
+	verdict := "stronger"
+	if change < 0 {
+		verdict = "weaker"
+	}
+	fmt.Printf("  %s %s %s\n", padRight(iconSuccess("Index"), 14), colorYellow(fmt.Sprintf("%.2f", last.Rate)),
+		colorForChange(change)(fmt.Sprintf("(%+.2f%%, %s is %s against the basket)", change, base, verdict)))
+	if len(skipped) > 0 {
+		printWarning("  No rate history for %s, left out of the basket\n", strings.Join(skipped, ", "))
+	}
+}
+
+// colorForChange is green for a rise and red for a fall.
+func colorForChange(change float64) func(string) string {
+	if change < 0 {
+		return colorRed
+	}
+	return colorGreen
+}
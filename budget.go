@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// handleBudget implements `nomad cv budget <amount> <from> <to> [--per
+// day|week|month]`, spreading a budget over days, weeks and 30-day months
+// in both currencies.
+func handleBudget(args []string) {
+	args, period, _ := popFlagValue(args, "--per")
+	if len(args) < 3 {
+		printError("Usage: nomad cv budget <amount> <from_currency> <to_currency> [--per day|week|month]\n")
+		printInfo("Example: nomad cv budget 60 usd thb\n")
+		os.Exit(1)
+	}
+
+	amount, err := evalExpression(args[0])
+	if err != nil || amount <= 0 {
+		printError("Error: Invalid amount '%s'\n", args[0])
+		os.Exit(1)
+	}
+	from, to := mustResolveCurrency(args[1]), mustResolveCurrency(args[2])
+
+	switch period = strings.ToLower(period); period {
+	case "", "daily":
+		period = "day"
+	case "weekly", "monthly":
+		period = strings.TrimSuffix(period, "ly")
+	}
+	days, ok := pricePeriods[period]
+	if !ok || period == "night" {
+		printError("Error: Unknown period '%s' (use day, week or month)\n", period)
+		os.Exit(1)
+	}
+
+	snapshotFallback = true
+	var rate float64
+	err = WithSpinner("Fetching exchange rates...", func() error {
+		var fetchErr error
+		rate, fetchErr = getExchangeRate(from, to)
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error getting exchange rate: %v\n", err)
+		os.Exit(1)
+	}
+
+	perDay := amount / days
+
+	fmt.Println()
+	printTitle("%s Budget of %s %s per %s\n", iconCurrency(""), formatAmount(amount, from), from, period)
+	fmt.Printf("  1 %s = %s %s\n\n", from, formatRate(rate), to)
+
+	table := NewTable("", from, to)
+	for _, row := range []struct {
+		label string
+		days  float64
+	}{{"Per day", 1}, {"Per week", 7}, {"Per 30 days", 30}} {
+		total := perDay * row.days
+		table.AddRow(row.label, formatAmount(total, from), colorGreen(formatAmount(total*rate, to)))
+	}
+	table.Print()
+	printSnapshotNotice()
+}
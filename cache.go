@@ -0,0 +1,413 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores provider responses so repeated lookups can skip the network.
+// Implementations must treat errors as misses; a broken cache never breaks
+// a command.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+// newCache returns the backend selected by the cache config key: memory
+// (the default, lasting one run), disk, or redis using cache_redis, e.g.
+// "redis://:password@localhost:6379/0".
+func newCache() (Cache, error) {
+	switch backend := strings.ToLower(config.Get("cache")); backend {
+	case "", "memory":
+		return newMemoryCache(), nil
+	case "disk":
+		return newDiskCache("cache")
+	case "redis":
+		return newRedisCache(config.Get("cache_redis"))
+	default:
+		return nil, fmt.Errorf("unknown cache backend '%s' (use memory, disk or redis)", backend)
+	}
+}
+
+var (
+	providerCache     Cache
+	providerCacheOnce sync.Once
+)
+
+// sharedCache returns the provider cache, falling back to memory when the
+// configured backend cannot be set up.
+func sharedCache() Cache {
+	providerCacheOnce.Do(func() {
+		cache, err := newCache()
+		if err != nil {
+			printWarning("Warning: %v, using in-memory cache\n", err)
+			cache = newMemoryCache()
+		}
+		providerCache = cache
+	})
+	return providerCache
+}
+
+// runCache holds entries for this run only.
+var runCache = newMemoryCache()
+
+// privateCache returns the cache for entries that depend on who is asking,
+// such as the forecast for the caller's IP-based location: the configured
+// backend, unless that is Redis, which other people share.
+func privateCache() Cache {
+	if strings.ToLower(config.Get("cache")) == "redis" {
+		return runCache
+	}
+	return sharedCache()
+}
+
+// cacheRefresh makes cachedFetch always fetch, still storing the results,
+// for long-running commands that need current data on every check.
+var cacheRefresh bool
+
+// cachedFetch returns the cached value for key, or calls fetch and caches
+// its result for ttl. Mock and replay runs bypass the cache so their output
+// never mixes with real data.
+func cachedFetch(key string, ttl time.Duration, fetch func() ([]byte, error)) ([]byte, error) {
+	return cachedFetchIn(sharedCache(), key, ttl, fetch)
+}
+
+// cachedDecode is cachedFetch for a response that has to decode before
+// it is worth keeping: decode runs on every body, and a fresh body is only
+// cached once decode accepts it, so an error page or a changed layout is
+// never served again from the cache.
+func cachedDecode(key string, ttl time.Duration, fetch func() ([]byte, error), decode func(body []byte) error) error {
+	return cachedDecodeIn(sharedCache(), key, ttl, fetch, decode)
+}
+
+// cachedDecodeIn is cachedDecode using a specific cache.
+func cachedDecodeIn(cache Cache, key string, ttl time.Duration, fetch func() ([]byte, error), decode func(body []byte) error) error {
+	fresh := false
+	body, err := cachedFetchIn(cache, key, ttl, func() ([]byte, error) {
+		body, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		fresh = true
+		if err := decode(body); err != nil {
+			return nil, err
+		}
+		return body, nil
+	})
+	if err != nil || fresh {
+		return err
+	}
+	return decode(body)
+}
+
+// statusError is an API answering with a status other than 200, for
+// callers that explain some statuses themselves.
+type statusError struct {
+	Status int
+}
+
+func (e *statusError) Error() string {
+	switch e.Status {
+	case http.StatusNotFound:
+		return "not found"
+	case http.StatusTooManyRequests:
+		return "rate limit reached, try again in a minute"
+	}
+	return fmt.Sprintf("API returned status code: %d", e.Status)
+}
+
+// fetchCachedJSON downloads a JSON response through the shared cache,
+// keeping it only when decode accepts it.
+func fetchCachedJSON(cacheKey, url string, ttl time.Duration, decode func(body []byte) error) error {
+	return cachedDecode(cacheKey, ttl, func() ([]byte, error) {
+		client := newHTTPClient(10 * time.Second)
+
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, &statusError{Status: resp.StatusCode}
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %v", err)
+		}
+		return body, nil
+	}, decode)
+}
+
+// cachedFetchIn is cachedFetch using a specific cache.
+func cachedFetchIn(cache Cache, key string, ttl time.Duration, fetch func() ([]byte, error)) ([]byte, error) {
+	if options.Mock || replayer != nil {
+		return fetch()
+	}
+
+	if value, ok := cache.Get(key); ok && !cacheRefresh {
+		logVerbose("cache hit %s", key)
+		return value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Set(key, value, ttl); err != nil {
+		logVerbose("cache write failed for %s: %v", key, err)
+	}
+	return value, nil
+}
+
+// memoryCache keeps entries for the lifetime of the process.
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is a cached value and when it stops being valid.
+type cacheEntry struct {
+	Value   []byte    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: make(map[string]cacheEntry)}
+}
+
+func (c *memoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.Expires) {
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+func (c *memoryCache) Set(key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = cacheEntry{Value: value, Expires: time.Now().Add(ttl)}
+	return nil
+}
+
+// diskCache keeps one JSON file per entry in a directory next to the config
+// file, so entries survive between runs.
+type diskCache struct {
+	dir string
+}
+
+func newDiskCache(name string) (*diskCache, error) {
+	dir, err := dataPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	return &diskCache{dir: dir}, nil
+}
+
+// path maps a key to a file name that is safe on every platform.
+func (c *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (c *diskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || time.Now().After(entry.Expires) {
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+func (c *diskCache) Set(key string, value []byte, ttl time.Duration) error {
+	data, err := json.Marshal(cacheEntry{Value: value, Expires: time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+
+	path := c.path(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// redisCache shares entries through a Redis server, speaking just enough of
+// the RESP protocol for AUTH, SELECT, GET and SET. Once the server can't
+// be reached it is left alone for the rest of the run, and entries are kept
+// in memory instead.
+type redisCache struct {
+	addr     string
+	username string
+	password string
+	db       int
+
+	down     atomic.Bool
+	fallback *memoryCache
+}
+
+// redisKeyPrefix namespaces nomad's keys in a shared Redis database.
+const redisKeyPrefix = "nomad:"
+
+func newRedisCache(rawURL string) (*redisCache, error) {
+	if rawURL == "" {
+		rawURL = "redis://localhost:6379"
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "redis" || u.Host == "" {
+		return nil, fmt.Errorf("invalid cache_redis '%s' (expected redis://[[user]:password@]host:port[/db])", rawURL)
+	}
+
+	cache := &redisCache{addr: u.Host, fallback: newMemoryCache()}
+	if !strings.Contains(u.Host, ":") {
+		cache.addr = u.Host + ":6379"
+	}
+	if u.User != nil {
+		cache.username = u.User.Username()
+		cache.password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if cache.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid Redis database '%s'", db)
+		}
+	}
+	return cache, nil
+}
+
+func (c *redisCache) Get(key string) ([]byte, bool) {
+	if c.down.Load() {
+		return c.fallback.Get(key)
+	}
+	reply, err := c.do("GET", redisKeyPrefix+key)
+	if err != nil || reply == nil {
+		return nil, false
+	}
+	return reply, true
+}
+
+func (c *redisCache) Set(key string, value []byte, ttl time.Duration) error {
+	if c.down.Load() {
+		return c.fallback.Set(key, value, ttl)
+	}
+	seconds := strconv.Itoa(max(1, int(ttl.Seconds())))
+	if _, err := c.do("SET", redisKeyPrefix+key, string(value), "EX", seconds); err != nil {
+		if c.down.Load() {
+			return c.fallback.Set(key, value, ttl)
+		}
+		return err
+	}
+	return nil
+}
+
+// do runs one command on a fresh connection, authenticating and selecting
+// the database first. A nil reply means the key does not exist. Failing to
+// connect marks the server down, so later lookups don't wait on it again.
+func (c *redisCache) do(args ...string) ([]byte, error) {
+	conn, err := net.DialTimeout("tcp", c.addr, 2*time.Second)
+	if err != nil {
+		if !c.down.Swap(true) {
+			logVerbose("Redis at %s is unreachable, caching in memory for this run: %v", c.addr, err)
+		}
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	var commands [][]string
+	switch {
+	case c.username != "":
+		// An ACL user, from Redis 6
+		commands = append(commands, []string{"AUTH", c.username, c.password})
+	case c.password != "":
+		commands = append(commands, []string{"AUTH", c.password})
+	}
+	if c.db != 0 {
+		commands = append(commands, []string{"SELECT", strconv.Itoa(c.db)})
+	}
+	commands = append(commands, args)
+
+	var reply []byte
+	for _, command := range commands {
+		if err := writeRESP(conn, command); err != nil {
+			return nil, err
+		}
+		if reply, err = readRESP(reader); err != nil {
+			return nil, err
+		}
+	}
+	return reply, nil
+}
+
+// writeRESP sends a command as an array of bulk strings.
+func writeRESP(conn net.Conn, args []string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err := conn.Write([]byte(b.String()))
+	return err
+}
+
+// readRESP reads a simple string, error, integer or bulk string reply.
+func readRESP(reader *bufio.Reader) ([]byte, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Redis reply: %v", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty Redis reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis reply: %s", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, fmt.Errorf("failed to read Redis reply: %v", err)
+		}
+		return data[:size], nil
+	default:
+		return nil, fmt.Errorf("unsupported Redis reply: %s", line)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Card is a shareable summary of a command's result, saved as a PNG with
+// --card instead of screenshotting the terminal.
+type Card struct {
+	Title string
+	Rows  []CardRow
+}
+
+// CardRow is one labelled value on a card.
+type CardRow struct {
+	Label string
+	Value string
+}
+
+// NewCard starts a card with the given title.
+func NewCard(title string) *Card {
+	return &Card{Title: title}
+}
+
+// Add appends a labelled value to the card.
+func (c *Card) Add(label, value string) *Card {
+	c.Rows = append(c.Rows, CardRow{Label: label, Value: value})
+	return c
+}
+
+// Card layout, in font pixels before scaling
+const (
+	cardScale    = 3
+	cardPadding  = 12
+	cardMinWidth = 220
+	cardGap      = 14
+)
+
+var (
+	cardBackground = color.RGBA{0x1e, 0x1e, 0x2e, 0xff}
+	cardAccent     = color.RGBA{0x89, 0xb4, 0xfa, 0xff}
+	cardLabel      = color.RGBA{0xa6, 0xad, 0xc8, 0xff}
+	cardValue      = color.RGBA{0xcd, 0xd6, 0xf4, 0xff}
+	cardMuted      = color.RGBA{0x6c, 0x70, 0x86, 0xff}
+)
+
+// SavePNG renders the card and writes it to path.
+func (c *Card) SavePNG(path string) error {
+	img := c.render()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// render draws the card with the built-in bitmap font and scales it up so
+// the pixel text stays crisp.
+func (c *Card) render() image.Image {
+	face := basicfont.Face7x13
+	lineHeight := face.Height + 6
+	footer := "nomad-cli · " + time.Now().Format("Jan 2, 2006 15:04")
+
+	labelWidth := 0
+	for _, row := range c.Rows {
+		labelWidth = max(labelWidth, textWidth(face, row.Label))
+	}
+
+	width := max(cardMinWidth, textWidth(face, c.Title), textWidth(face, footer))
+	for _, row := range c.Rows {
+		width = max(width, labelWidth+cardGap+textWidth(face, row.Value))
+	}
+	width += 2 * cardPadding
+	height := cardPadding + 4 + lineHeight*(len(c.Rows)+3) + cardPadding
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(cardBackground), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, width, 3), image.NewUniform(cardAccent), image.Point{}, draw.Src)
+
+	y := cardPadding + 4 + face.Ascent
+	drawText(img, face, cardPadding, y, c.Title, cardAccent)
+	y += lineHeight + lineHeight/2
+
+	for _, row := range c.Rows {
+		drawText(img, face, cardPadding, y, row.Label, cardLabel)
+		drawText(img, face, cardPadding+labelWidth+cardGap, y, row.Value, cardValue)
+		y += lineHeight
+	}
+
+	y += lineHeight / 2
+	drawText(img, face, cardPadding, y, footer, cardMuted)
+
+	return scaleImage(img, cardScale)
+}
+
+// cardText reduces text to what the bitmap font can draw, which is ASCII
+// only. Colors are stripped and the degree sign is dropped ("31°C" becomes
+// "31C").
+func cardText(text string) string {
+	text = strings.ReplaceAll(stripANSI(text), "·", "-")
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+func textWidth(face font.Face, text string) int {
+	return font.MeasureString(face, cardText(text)).Ceil()
+}
+
+func drawText(img draw.Image, face font.Face, x, y int, text string, c color.Color) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(cardText(text))
+}
+
+// scaleImage enlarges img by an integer factor using nearest-neighbour
+// sampling.
+func scaleImage(img *image.RGBA, factor int) *image.RGBA {
+	bounds := img.Bounds()
+	scaled := image.NewRGBA(image.Rect(0, 0, bounds.Dx()*factor, bounds.Dy()*factor))
+	for y := 0; y < scaled.Bounds().Dy(); y++ {
+		for x := 0; x < scaled.Bounds().Dx(); x++ {
+			scaled.SetRGBA(x, y, img.RGBAAt(bounds.Min.X+x/factor, bounds.Min.Y+y/factor))
+		}
+	}
+	return scaled
+}
+
+// saveCard writes card to path when --card was given, exiting on failure.
+func saveCard(card *Card, path string) {
+	if path == "" {
+		return
+	}
+	if err := card.SavePNG(path); err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+	printSuccess("Saved card to %s\n", path)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Denominations are the banknotes in circulation for a currency.
+type Denominations struct {
+	// Notes are listed smallest first
+	Notes []float64
+	// ATMStep is what ATMs dispense in multiples of, usually the smallest
+	// note they stock
+	ATMStep float64
+}
+
+// banknotes covers the currencies travellers most often withdraw. Coins are
+// left out; whatever the notes can't make up is paid in change.
+var banknotes = map[string]Denominations{
+	"AED": {[]float64{5, 10, 20, 50, 100, 200, 500, 1000}, 50},
+	"ARS": {[]float64{100, 200, 500, 1000, 2000, 10000, 20000}, 1000},
+	"AUD": {[]float64{5, 10, 20, 50, 100}, 10},
+	"BRL": {[]float64{2, 5, 10, 20, 50, 100, 200}, 10},
+	"CAD": {[]float64{5, 10, 20, 50, 100}, 20},
+	"CHF": {[]float64{10, 20, 50, 100, 200, 1000}, 10},
+	"CLP": {[]float64{1000, 2000, 5000, 10000, 20000}, 5000},
+	"CNY": {[]float64{1, 5, 10, 20, 50, 100}, 100},
+	"COP": {[]float64{2000, 5000, 10000, 20000, 50000, 100000}, 10000},
+	"CZK": {[]float64{100, 200, 500, 1000, 2000, 5000}, 100},
+	"DKK": {[]float64{50, 100, 200, 500, 1000}, 100},
+	"EGP": {[]float64{5, 10, 20, 50, 100, 200}, 100},
+	"EUR": {[]float64{5, 10, 20, 50, 100, 200}, 10},
+	"GBP": {[]float64{5, 10, 20, 50}, 10},
+	"GEL": {[]float64{5, 10, 20, 50, 100, 200}, 10},
+	"HKD": {[]float64{10, 20, 50, 100, 500, 1000}, 100},
+	"HUF": {[]float64{500, 1000, 2000, 5000, 10000, 20000}, 5000},
+	"IDR": {[]float64{1000, 2000, 5000, 10000, 20000, 50000, 100000}, 50000},
+	"INR": {[]float64{10, 20, 50, 100, 200, 500}, 100},
+	"JPY": {[]float64{1000, 2000, 5000, 10000}, 1000},
+	"KRW": {[]float64{1000, 5000, 10000, 50000}, 10000},
+	"LAK": {[]float64{1000, 2000, 5000, 10000, 20000, 50000, 100000}, 50000},
+	"MAD": {[]float64{20, 50, 100, 200}, 100},
+	"MXN": {[]float64{20, 50, 100, 200, 500, 1000}, 100},
+	"MYR": {[]float64{1, 5, 10, 20, 50, 100}, 50},
+	"NOK": {[]float64{50, 100, 200, 500, 1000}, 100},
+	"NZD": {[]float64{5, 10, 20, 50, 100}, 20},
+	"PEN": {[]float64{10, 20, 50, 100, 200}, 10},
+	"PHP": {[]float64{20, 50, 100, 200, 500, 1000}, 100},
+	"PLN": {[]float64{10, 20, 50, 100, 200, 500}, 10},
+	"SEK": {[]float64{20, 50, 100, 200, 500, 1000}, 100},
+	"SGD": {[]float64{2, 5, 10, 50, 100}, 10},
+	"THB": {[]float64{20, 50, 100, 500, 1000}, 100},
+	"TRY": {[]float64{5, 10, 20, 50, 100, 200}, 10},
+	"TWD": {[]float64{100, 200, 500, 1000, 2000}, 100},
+	"USD": {[]float64{1, 5, 10, 20, 50, 100}, 20},
+	"VND": {[]float64{1000, 2000, 5000, 10000, 20000, 50000, 100000, 200000, 500000}, 100000},
+	"ZAR": {[]float64{10, 20, 50, 100, 200}, 10},
+}
+
+// NoteCount is how many of one banknote make up part of an amount.
+type NoteCount struct {
+	Note  float64
+	Count int
+}
+
+// Breakdown splits amount into the fewest notes, largest first, returning
+// what is left over for coins.
+func (d Denominations) Breakdown(amount float64) ([]NoteCount, float64) {
+	var counts []NoteCount
+	remaining := amount
+	for i := len(d.Notes) - 1; i >= 0; i-- {
+		note := d.Notes[i]
+		if count := int(math.Floor(remaining / note)); count > 0 {
+			counts = append(counts, NoteCount{note, count})
+			remaining -= float64(count) * note
+		}
+	}
+	return counts, remaining
+}
+
+// ATMAmounts returns the withdrawals either side of amount that an ATM can
+// actually dispense, or just one when amount already is one.
+func (d Denominations) ATMAmounts(amount float64) []float64 {
+	below := math.Floor(amount/d.ATMStep) * d.ATMStep
+	above := math.Ceil(amount/d.ATMStep) * d.ATMStep
+	switch {
+	case below == above:
+		return []float64{below}
+	case below == 0:
+		return []float64{above}
+	default:
+		return []float64{below, above}
+	}
+}
+
+// formatBreakdown renders note counts as "7 × 1000, 1 × 500".
+func formatBreakdown(counts []NoteCount) string {
+	parts := make([]string, len(counts))
+	for i, count := range counts {
+		parts[i] = fmt.Sprintf("%d × %s", count.Count, formatFeeAmount(count.Note))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printCashBreakdown suggests the notes for amount and the nearest ATM
+// withdrawals, with what each costs at rate from the source currency. It
+// returns the breakdown for the share card.
+func printCashBreakdown(amount, rate float64, from, to string) string {
+	denominations, ok := banknotes[to]
+	if !ok {
+		printWarning("  No banknote data for %s\n", to)
+		return ""
+	}
+
+	counts, coins := denominations.Breakdown(amount)
+	breakdown := formatBreakdown(counts)
+	if breakdown == "" {
+		breakdown = "less than the smallest note"
+	}
+	if coins >= 0.5 {
+		breakdown += colorCyan(fmt.Sprintf(" + %s %s in coins", formatFeeAmount(math.Round(coins)), to))
+	}
+	fmt.Printf("  %s Cash: %s\n", padRight(iconCurrency(""), 3), breakdown)
+
+	var withdrawals []string
+	for _, withdrawal := range denominations.ATMAmounts(amount) {
+		withdrawals = append(withdrawals, fmt.Sprintf("%s %s %s", colorYellow(formatFeeAmount(withdrawal)), to,
+			colorCyan(fmt.Sprintf("(%s %s)", formatAmount(withdrawal/rate, from), from))))
+	}
+	fmt.Printf("  %s ATM: %s\n", padRight(iconCurrency(""), 3), strings.Join(withdrawals, " or "))
+
+	return stripANSI(breakdown)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"math"
+	"strings"
+)
+
+// chartHeight is the number of rows time-series commands draw.
+const chartHeight = 6
+
+// Chart draws a time series in the terminal as block bars, for rate
+// history and anything else measured over time.
+type Chart struct {
+	Values []float64
+	// Height is the number of rows; 1 draws a plain sparkline without an
+	// axis
+	Height int
+	// StartLabel and EndLabel go under the first and last bar
+	StartLabel, EndLabel string
+	// FormatValue labels the top and bottom of the axis
+	FormatValue func(float64) string
+	Color       func(string) string
+}
+
+// NewChart returns a one-row chart of values.
+func NewChart(values []float64) *Chart {
+	return &Chart{Values: values, Height: 1, FormatValue: formatRate, Color: colorCyan}
+}
+
+// SetHeight sets the number of rows.
+func (c *Chart) SetHeight(rows int) *Chart {
+	c.Height = max(rows, 1)
+	return c
+}
+
+// SetLabels sets the labels under the start and end of the chart.
+func (c *Chart) SetLabels(start, end string) *Chart {
+	c.StartLabel, c.EndLabel = start, end
+	return c
+}
+
+// SetFormat sets how axis values are printed.
+func (c *Chart) SetFormat(format func(float64) string) *Chart {
+	c.FormatValue = format
+	return c
+}
+
+// Render returns the chart, indented to sit under a title, fitted to the
+// terminal width. Long series are averaged down to one bar per column.
+func (c *Chart) Render() string {
+	if len(c.Values) == 0 {
+		return ""
+	}
+
+	low, high := c.Values[0], c.Values[0]
+	for _, v := range c.Values {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+
+	var rows []string
+	axisWidth := 0
+	if c.Height == 1 {
+		rows = []string{sparkline(downsample(c.Values, terminalWidth()-4))}
+	} else {
+		top, bottom := c.FormatValue(high), c.FormatValue(low)
+		axisWidth = max(displayWidth(top), displayWidth(bottom)) + 2
+		bars := blockRows(downsample(c.Values, terminalWidth()-4-axisWidth), low, high, c.Height)
+		for i, bar := range bars {
+			label := ""
+			switch i {
+			case 0:
+				label = top
+			case len(bars) - 1:
+				label = bottom
+			}
+			rows = append(rows, strings.Repeat(" ", axisWidth-2-displayWidth(label))+label+" ┤"+c.Color(bar))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		if c.Height == 1 {
+			row = c.Color(row)
+		}
+		b.WriteString("  " + row + "\n")
+	}
+
+	// Date labels line up with the bars, past the axis
+	width := displayWidth(stripANSI(rows[len(rows)-1])) - axisWidth
+	if c.StartLabel != "" && width > len(c.StartLabel)+len(c.EndLabel) {
+		b.WriteString("  " + strings.Repeat(" ", axisWidth) + padRight(c.StartLabel, width-len(c.EndLabel)) + c.EndLabel + "\n")
+	}
+	return b.String()
+}
+
+// blockRows draws values as columns of eighth-height blocks, top row
+// first. The lowest value still gets a sliver so no column is empty.
+func blockRows(values []float64, low, high float64, height int) []string {
+	steps := height * len(sparkBlocks)
+	rows := make([][]rune, height)
+	for i := range rows {
+		rows[i] = make([]rune, len(values))
+	}
+
+	for col, v := range values {
+		level := steps / 2
+		if high > low {
+			level = 1 + int((v-low)/(high-low)*float64(steps-1))
+		}
+		for row := 0; row < height; row++ {
+			fill := level - (height-1-row)*len(sparkBlocks)
+			switch {
+			case fill <= 0:
+				rows[row][col] = ' '
+			case fill >= len(sparkBlocks):
+				rows[row][col] = sparkBlocks[len(sparkBlocks)-1]
+			default:
+				rows[row][col] = sparkBlocks[fill-1]
+			}
+		}
+	}
+
+	out := make([]string, height)
+	for i, row := range rows {
+		out[i] = string(row)
+	}
+	return out
+}
+
+// downsample averages values into at most n buckets.
+func downsample(values []float64, n int) []float64 {
+	if n < 1 || len(values) <= n {
+		return values
+	}
+
+	out := make([]float64, n)
+	for i := range out {
+		start, end := i*len(values)/n, (i+1)*len(values)/n
+		var sum float64
+		for _, v := range values[start:end] {
+			sum += v
+		}
+		out[i] = sum / float64(end-start)
+	}
+	return out
+}
+
+// sparkBlocks are the eighth-height bars used to draw sparklines.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a single line of bars scaled between their
+// minimum and maximum.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := len(sparkBlocks) / 2
+		if high > low {
+			level = int((v - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
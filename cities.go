@@ -0,0 +1,106 @@
+package main
+
+import "strings"
+
+// City is an entry in the offline city dataset used for interactive
+// selection and lookups that should work without geocoding.
+type City struct {
+	Name     string
+	Country  string
+	Lat      float64
+	Lon      float64
+	Timezone string
+}
+
+// Label returns the "City, Country" form shown to users and passed to
+// providers as a query.
+func (c City) Label() string {
+	return c.Name + ", " + c.Country
+}
+
+// cities lists popular digital nomad bases and major hubs.
+var cities = []City{
+	{"Amsterdam", "Netherlands", 52.3676, 4.9041, "Europe/Amsterdam"},
+	{"Athens", "Greece", 37.9838, 23.7275, "Europe/Athens"},
+	{"Auckland", "New Zealand", -36.8485, 174.7633, "Pacific/Auckland"},
+	{"Austin", "United States", 30.2672, -97.7431, "America/Chicago"},
+	{"Bali", "Indonesia", -8.6500, 115.2167, "Asia/Makassar"},
+	{"Bangkok", "Thailand", 13.7563, 100.5018, "Asia/Bangkok"},
+	{"Barcelona", "Spain", 41.3874, 2.1686, "Europe/Madrid"},
+	{"Berlin", "Germany", 52.5200, 13.4050, "Europe/Berlin"},
+	{"Bogota", "Colombia", 4.7110, -74.0721, "America/Bogota"},
+	{"Budapest", "Hungary", 47.4979, 19.0402, "Europe/Budapest"},
+	{"Buenos Aires", "Argentina", -34.6037, -58.3816, "America/Argentina/Buenos_Aires"},
+	{"Cape Town", "South Africa", -33.9249, 18.4241, "Africa/Johannesburg"},
+	{"Chiang Mai", "Thailand", 18.7883, 98.9853, "Asia/Bangkok"},
+	{"Copenhagen", "Denmark", 55.6761, 12.5683, "Europe/Copenhagen"},
+	{"Da Nang", "Vietnam", 16.0544, 108.2022, "Asia/Ho_Chi_Minh"},
+	{"Dubai", "United Arab Emirates", 25.2048, 55.2708, "Asia/Dubai"},
+	{"Dublin", "Ireland", 53.3498, -6.2603, "Europe/Dublin"},
+	{"Florianopolis", "Brazil", -27.5954, -48.5480, "America/Sao_Paulo"},
+	{"Hanoi", "Vietnam", 21.0278, 105.8342, "Asia/Ho_Chi_Minh"},
+	{"Ho Chi Minh City", "Vietnam", 10.8231, 106.6297, "Asia/Ho_Chi_Minh"},
+	{"Hong Kong", "Hong Kong", 22.3193, 114.1694, "Asia/Hong_Kong"},
+	{"Istanbul", "Turkey", 41.0082, 28.9784, "Europe/Istanbul"},
+	{"Kuala Lumpur", "Malaysia", 3.1390, 101.6869, "Asia/Kuala_Lumpur"},
+	{"Las Palmas", "Spain", 28.1235, -15.4363, "Atlantic/Canary"},
+	{"Lima", "Peru", -12.0464, -77.0428, "America/Lima"},
+	{"Lisbon", "Portugal", 38.7223, -9.1393, "Europe/Lisbon"},
+	{"London", "United Kingdom", 51.5074, -0.1278, "Europe/London"},
+	{"Los Angeles", "United States", 34.0522, -118.2437, "America/Los_Angeles"},
+	{"Madeira", "Portugal", 32.6669, -16.9241, "Atlantic/Madeira"},
+	{"Madrid", "Spain", 40.4168, -3.7038, "Europe/Madrid"},
+	{"Manila", "Philippines", 14.5995, 120.9842, "Asia/Manila"},
+	{"Medellin", "Colombia", 6.2442, -75.5812, "America/Bogota"},
+	{"Melbourne", "Australia", -37.8136, 144.9631, "Australia/Melbourne"},
+	{"Mexico City", "Mexico", 19.4326, -99.1332, "America/Mexico_City"},
+	{"Miami", "United States", 25.7617, -80.1918, "America/New_York"},
+	{"Montreal", "Canada", 45.5017, -73.5673, "America/Toronto"},
+	{"Mumbai", "India", 19.0760, 72.8777, "Asia/Kolkata"},
+	{"New York", "United States", 40.7128, -74.0060, "America/New_York"},
+	{"Oaxaca", "Mexico", 17.0732, -96.7266, "America/Mexico_City"},
+	{"Paris", "France", 48.8566, 2.3522, "Europe/Paris"},
+	{"Penang", "Malaysia", 5.4164, 100.3327, "Asia/Kuala_Lumpur"},
+	{"Phuket", "Thailand", 7.8804, 98.3923, "Asia/Bangkok"},
+	{"Playa del Carmen", "Mexico", 20.6296, -87.0739, "America/Cancun"},
+	{"Prague", "Czech Republic", 50.0755, 14.4378, "Europe/Prague"},
+	{"Rio de Janeiro", "Brazil", -22.9068, -43.1729, "America/Sao_Paulo"},
+	{"Rome", "Italy", 41.9028, 12.4964, "Europe/Rome"},
+	{"San Francisco", "United States", 37.7749, -122.4194, "America/Los_Angeles"},
+	{"Santiago", "Chile", -33.4489, -70.6693, "America/Santiago"},
+	{"Seoul", "South Korea", 37.5665, 126.9780, "Asia/Seoul"},
+	{"Singapore", "Singapore", 1.3521, 103.8198, "Asia/Singapore"},
+	{"Sofia", "Bulgaria", 42.6977, 23.3219, "Europe/Sofia"},
+	{"Split", "Croatia", 43.5081, 16.4402, "Europe/Zagreb"},
+	{"Sydney", "Australia", -33.8688, 151.2093, "Australia/Sydney"},
+	{"Taipei", "Taiwan", 25.0330, 121.5654, "Asia/Taipei"},
+	{"Tallinn", "Estonia", 59.4370, 24.7536, "Europe/Tallinn"},
+	{"Tbilisi", "Georgia", 41.7151, 44.8271, "Asia/Tbilisi"},
+	{"Tokyo", "Japan", 35.6762, 139.6503, "Asia/Tokyo"},
+	{"Toronto", "Canada", 43.6532, -79.3832, "America/Toronto"},
+	{"Valencia", "Spain", 39.4699, -0.3763, "Europe/Madrid"},
+	{"Vancouver", "Canada", 49.2827, -123.1207, "America/Vancouver"},
+	{"Vienna", "Austria", 48.2082, 16.3738, "Europe/Vienna"},
+	{"Warsaw", "Poland", 52.2297, 21.0122, "Europe/Warsaw"},
+}
+
+// cityLabels returns the display labels of all cities in the dataset.
+func cityLabels() []string {
+	labels := make([]string, len(cities))
+	for i, c := range cities {
+		labels[i] = c.Label()
+	}
+	return labels
+}
+
+// findCity looks up a city in the dataset by name, ignoring case and
+// treating "-" and "_" as spaces so "new-york" matches "New York".
+func findCity(name string) *City {
+	name = strings.NewReplacer("-", " ", "_", " ").Replace(name)
+	for i := range cities {
+		if strings.EqualFold(cities[i].Name, name) {
+			return &cities[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// getClimateHistory returns the daily weather at a point over the last
+// normalsYears full years from Open-Meteo's historical archive, with the
+// first and last year.
+func getClimateHistory(lat, lon float64, today time.Time) (*openMeteoDaily, int, int, error) {
+	toYear := today.Year() - 1
+	fromYear := toYear - normalsYears + 1
+	var history openMeteoDaily
+	err := fetchCachedJSON(fmt.Sprintf("climate:%.2f,%.2f:%d", lat, lon, fromYear),
+		fmt.Sprintf("https://archive-api.open-meteo.com/v1/archive?latitude=%.4f&longitude=%.4f&start_date=%d-01-01&end_date=%d-12-31&daily=temperature_2m_max,temperature_2m_min,precipitation_sum&timezone=auto",
+			lat, lon, fromYear, toYear), climateCacheTTL,
+		func(body []byte) error {
+			if err := json.Unmarshal(body, &history); err != nil {
+				return schemaMismatch("archive-api.open-meteo.com", "invalid JSON: "+err.Error(), body)
+			}
+			daily := history.Daily
+			if len(daily.Time) == 0 || len(daily.MaxC) != len(daily.Time) || len(daily.MinC) != len(daily.Time) || len(daily.PrecipMM) != len(daily.Time) {
+				return schemaMismatch("archive-api.open-meteo.com", "no daily history", body)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return &history, fromYear, toYear, nil
+}
+
+// MonthClimate is the typical weather of one calendar month.
+type MonthClimate struct {
+	Month time.Month
+	// MaxC and MinC are the average daily high and low
+	MaxC, MinC float64
+	// RainMM is the average total for the month and RainyDays the average
+	// number of days with at least 1 mm
+	RainMM    float64
+	RainyDays float64
+}
+
+// monthlyClimate averages daily history into the twelve calendar months.
+func monthlyClimate(history *openMeteoDaily) []MonthClimate {
+	type totals struct {
+		maxC, minC, rain float64
+		rainyDays, days  int
+		years            map[int]bool
+	}
+	var months [12]totals
+	daily := history.Daily
+	for i, day := range daily.Time {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil || daily.MaxC[i] == nil || daily.MinC[i] == nil || daily.PrecipMM[i] == nil {
+			continue
+		}
+		month := &months[date.Month()-1]
+		if month.years == nil {
+			month.years = make(map[int]bool)
+		}
+		month.years[date.Year()] = true
+		month.maxC += *daily.MaxC[i]
+		month.minC += *daily.MinC[i]
+		month.rain += *daily.PrecipMM[i]
+		if *daily.PrecipMM[i] >= 1 {
+			month.rainyDays++
+		}
+		month.days++
+	}
+
+	var climate []MonthClimate
+	for i, month := range months {
+		if month.days == 0 {
+			continue
+		}
+		days, years := float64(month.days), float64(len(month.years))
+		climate = append(climate, MonthClimate{
+			Month:     time.Month(i + 1),
+			MaxC:      month.maxC / days,
+			MinC:      month.minC / days,
+			RainMM:    month.rain / years,
+			RainyDays: float64(month.rainyDays) / years,
+		})
+	}
+	return climate
+}
+
+// handleWeatherClimate implements `nomad weather <place> --climate`: typical
+// highs, lows and rainfall for each month, to decide when to base
+// somewhere.
+func handleWeatherClimate(query string) {
+	var weather *Weather
+	var history *openMeteoDaily
+	var fromYear, toYear int
+	err := WithSpinner("Fetching climate history...", func() error {
+		var fetchErr error
+		if weather, fetchErr = currentWeather(query); fetchErr != nil {
+			return fetchErr
+		}
+		if !weather.HasCoords {
+			return fmt.Errorf("no coordinates for %s", weather.Location)
+		}
+		history, fromYear, toYear, fetchErr = getClimateHistory(weather.Lat, weather.Lon, time.Now())
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	climate := monthlyClimate(history)
+	if len(climate) == 0 {
+		printError("Error: No climate history for %s\n", weather.Location)
+		os.Exit(1)
+	}
+
+	maxRain := 0.0
+	for _, month := range climate {
+		maxRain = math.Max(maxRain, month.RainMM)
+	}
+
+	fmt.Println()
+	printTitle("%s Climate in %s, %d–%d averages\n", iconWeather(""), weather.Location, fromYear, toYear)
+	table := NewTable("Month", "High", "Low", "Rainfall", "Rainy days", "")
+	thisMonth := time.Now().Month()
+	for _, month := range climate {
+		name := month.Month.String()[:3]
+		if month.Month == thisMonth {
+			name = colorBold(name + " ←")
+		}
+		bar := ""
+		if maxRain > 0 {
+			bar = strings.Repeat("▇", int(math.Round(month.RainMM/maxRain*10)))
+		}
+		table.AddRow(name, colorYellow(formatTemp(month.MaxC)), formatTemp(month.MinC),
+			formatRainfall(month.RainMM), fmt.Sprintf("%.0f", month.RainyDays), colorCyan(bar))
+	}
+	table.Print()
+
+	// The driest months are the easy ones to recommend; the rest depends on
+	// what temperatures you like
+	driest := climate[0]
+	for _, month := range climate {
+		if month.RainyDays < driest.RainyDays {
+			driest = month
+		}
+	}
+	fmt.Println()
+	fmt.Printf("  %s %s %s\n", padRight(iconSuccess("Driest month"), 14), driest.Month,
+		colorCyan(fmt.Sprintf("(%.0f rainy days, %s)", driest.RainyDays, formatRainfall(driest.RainMM))))
+
+	addSource(Source{What: "Climate averages", From: "Open-Meteo historical archive", Confidence: confidenceMedium,
+		Updated: fmt.Sprintf("%d–%d", fromYear, toYear)})
+	printSources()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommand describes an external tool used to access the clipboard.
+type clipboardCommand struct {
+	name string
+	args []string
+}
+
+// clipboardCommands returns the candidate tools to read from or write to the
+// clipboard on the current platform, in order of preference.
+func clipboardCommands(write bool) ([]clipboardCommand, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		if write {
+			return []clipboardCommand{{"pbcopy", nil}}, nil
+		}
+		return []clipboardCommand{{"pbpaste", nil}}, nil
+	case "windows":
+		if write {
+			return []clipboardCommand{{"clip", nil}}, nil
+		}
+		return []clipboardCommand{{"powershell", []string{"-NoProfile", "-Command", "Get-Clipboard"}}}, nil
+	case "linux", "freebsd", "openbsd":
+		// Wayland first, then the common X11 tools
+		if write {
+			return []clipboardCommand{
+				{"wl-copy", nil},
+				{"xclip", []string{"-selection", "clipboard"}},
+				{"xsel", []string{"--clipboard", "--input"}},
+			}, nil
+		}
+		return []clipboardCommand{
+			{"wl-paste", []string{"--no-newline"}},
+			{"xclip", []string{"-selection", "clipboard", "-o"}},
+			{"xsel", []string{"--clipboard", "--output"}},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+}
+
+// findClipboardCommand returns the first clipboard tool that is installed.
+func findClipboardCommand(write bool) (*exec.Cmd, error) {
+	candidates, err := clipboardCommands(write)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate.name); err == nil {
+			return exec.Command(candidate.name, candidate.args...), nil
+		}
+		names = append(names, candidate.name)
+	}
+	return nil, fmt.Errorf("no clipboard tool found (install one of: %s)", strings.Join(names, ", "))
+}
+
+// ReadClipboard returns the current text contents of the clipboard.
+func ReadClipboard() (string, error) {
+	cmd, err := findClipboardCommand(false)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read clipboard: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// WriteClipboard replaces the clipboard contents with text.
+func WriteClipboard(text string) error {
+	cmd, err := findClipboardCommand(true)
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to write clipboard: %v", err)
+	}
+	return nil
+}
@@ -37,33 +37,42 @@ const (
 	IconJitter   = "📈"
 )
 
+// paint wraps text in the given color code, or returns it untouched when
+// the terminal does not support ANSI colors
+func paint(code, text string) string {
+	if !ansiEnabled {
+		return text
+	}
+	return code + text + Reset
+}
+
 // Color functions for easy use
 func colorRed(text string) string {
-	return Red + text + Reset
+	return paint(Red, text)
 }
 
 func colorGreen(text string) string {
-	return Green + text + Reset
+	return paint(Green, text)
 }
 
 func colorYellow(text string) string {
-	return Yellow + text + Reset
+	return paint(Yellow, text)
 }
 
 func colorBlue(text string) string {
-	return Blue + text + Reset
+	return paint(Blue, text)
 }
 
 func colorMagenta(text string) string {
-	return Magenta + text + Reset
+	return paint(Magenta, text)
 }
 
 func colorCyan(text string) string {
-	return Cyan + text + Reset
+	return paint(Cyan, text)
 }
 
 func colorBold(text string) string {
-	return Bold + text + Reset
+	return paint(Bold, text)
 }
 
 // Print functions with colors
@@ -89,6 +98,9 @@ func printTitle(format string, args ...interface{}) {
 
 // Icon functions for easy use
 func iconWithColor(icon, text string, colorFunc func(string) string) string {
+	if options.Plain {
+		return text
+	}
 	return colorFunc(icon + " " + text)
 }
 
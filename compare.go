@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateQuote is what one way of paying gives for an amount.
+type RateQuote struct {
+	Source string
+	// Rate is the units of the target currency per unit of the source
+	Rate float64
+	// Fee is charged on top, in the source currency
+	Fee float64
+	Err error
+}
+
+// Received is the target amount left after the fee.
+func (q RateQuote) Received(amount float64) float64 {
+	return (amount - q.Fee) * q.Rate
+}
+
+// quoteSources are compared by `nomad cv compare`, mid-market first.
+var quoteSources = []struct {
+	Name  string
+	Quote func(amount float64, from, to string) (rate, fee float64, err error)
+}{
+	{"Mid-market", func(amount float64, from, to string) (float64, float64, error) {
+		rate, err := getExchangeRate(from, to)
+		return rate, 0, err
+	}},
+	{"Wise", wiseQuote},
+	{"Visa", visaQuote},
+	{"Mastercard", mastercardQuote},
+}
+
+// handleCompare implements `nomad cv compare <amount> <from> <to>`.
+func handleCompare(args []string) {
+	if len(args) < 3 {
+		printError("Usage: nomad cv compare <amount> <from_currency> <to_currency>\n")
+		printInfo("Example: nomad cv compare 1000 usd thb\n")
+		os.Exit(1)
+	}
+	amount, err := evalExpression(args[0])
+	if err != nil || amount <= 0 {
+		printError("Error: Invalid amount '%s'\n", args[0])
+		os.Exit(1)
+	}
+	from, to := mustResolveCurrency(args[1]), mustResolveCurrency(args[2])
+	if isCrypto(from) || isCrypto(to) {
+		printError("Error: cv compare only covers fiat currencies\n")
+		os.Exit(1)
+	}
+
+	quotes := make([]RateQuote, len(quoteSources))
+	WithSpinner("Fetching quotes...", func() error {
+		var wg sync.WaitGroup
+		for i, source := range quoteSources {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				quotes[i].Source = source.Name
+				quotes[i].Rate, quotes[i].Fee, quotes[i].Err = source.Quote(amount, from, to)
+			}(i)
+		}
+		wg.Wait()
+		return nil
+	})
+
+	mid := quotes[0]
+	if mid.Err != nil {
+		printError("Error getting exchange rate: %v\n", mid.Err)
+		os.Exit(1)
+	}
+	best := mid.Received(amount)
+
+	fmt.Println()
+	printTitle("%s %s %s → %s\n", iconCurrency(""), formatGrouped(amount), from, to)
+
+	table := NewTable("Source", "Rate", "Fee", "You get", "vs mid-market")
+	for _, quote := range quotes {
+		if quote.Err != nil {
+			logVerbose("%s quote failed: %v", quote.Source, quote.Err)
+			table.AddRow(quote.Source, colorRed("unavailable"), "", "", "")
+			continue
+		}
+
+		fee := "none"
+		if quote.Fee > 0 {
+			fee = fmt.Sprintf("%.2f %s", quote.Fee, from)
+		}
+		received := quote.Received(amount)
+		spread := ""
+		if quote.Source != mid.Source {
+			// What this way of paying costs, in the source currency
+			lost := (best - received) / mid.Rate
+			spread = colorYellow(fmt.Sprintf("%.2f%% (%.2f %s)", (best-received)/best*100, lost, from))
+		}
+		table.AddRow(quote.Source, formatRate(quote.Rate), fee, colorGreen(formatGrouped(received)+" "+to), spread)
+	}
+	table.Print()
+
+	fmt.Println()
+	printInfo("Card network rates leave out your bank's foreign transaction fee, often 0-3%%\n")
+	printSnapshotNotice()
+}
+
+// quoteCacheTTL is how long a provider quote is reused.
+const quoteCacheTTL = 30 * time.Minute
+
+// wiseComparison is the part of Wise's public price comparison that
+// describes Wise itself.
+type wiseComparison struct {
+	Providers []struct {
+		Alias  string `json:"alias"`
+		Quotes []struct {
+			Rate float64 `json:"rate"`
+			Fee  float64 `json:"fee"`
+		} `json:"quotes"`
+	} `json:"providers"`
+}
+
+// wiseQuote asks Wise what it charges to send amount from one currency to
+// another.
+func wiseQuote(amount float64, from, to string) (float64, float64, error) {
+	var comparison wiseComparison
+	err := fetchCachedJSON(fmt.Sprintf("quote:wise:%s:%s:%g", from, to, amount),
+		fmt.Sprintf("https://api.wise.com/v4/comparisons/?sourceCurrency=%s&targetCurrency=%s&sendAmount=%g", from, to, amount), quoteCacheTTL,
+		func(body []byte) error {
+			if err := json.Unmarshal(body, &comparison); err != nil {
+				return fmt.Errorf("failed to parse JSON response: %v", err)
+			}
+			return nil
+		})
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, provider := range comparison.Providers {
+		if provider.Alias == "wise" && len(provider.Quotes) > 0 {
+			return provider.Quotes[0].Rate, provider.Quotes[0].Fee, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("no Wise quote for %s/%s", from, to)
+}
+
+// visaQuote looks up Visa's published rate. Visa quotes the cardholder's
+// currency per unit of the currency spent.
+func visaQuote(amount float64, from, to string) (float64, float64, error) {
+	date := time.Now().UTC().Format("01/02/2006")
+	var rate float64
+	err := fetchCachedJSON(fmt.Sprintf("quote:visa:%s:%s", from, to),
+		fmt.Sprintf("https://www.visa.co.uk/cmsapi/fx/rates?amount=%g&fee=0&utcConvertedDate=%s&exchangedate=%s&fromCurr=%s&toCurr=%s",
+			amount, date, date, from, to), quoteCacheTTL,
+		func(body []byte) error {
+			var response struct {
+				OriginalValues struct {
+					FxRateVisa string `json:"fxRateVisa"`
+				} `json:"originalValues"`
+			}
+			if err := json.Unmarshal(body, &response); err != nil {
+				return fmt.Errorf("failed to parse JSON response: %v", err)
+			}
+			var err error
+			if rate, err = strconv.ParseFloat(response.OriginalValues.FxRateVisa, 64); err != nil || rate <= 0 {
+				return fmt.Errorf("no Visa rate for %s/%s", from, to)
+			}
+			return nil
+		})
+	if err != nil {
+		return 0, 0, err
+	}
+	return 1 / rate, 0, nil
+}
+
+// mastercardQuote looks up Mastercard's published rate, which is also in
+// the cardholder's currency per unit spent.
+func mastercardQuote(amount float64, from, to string) (float64, float64, error) {
+	var response struct {
+		Data struct {
+			ConversionRate float64 `json:"conversionRate"`
+			ErrorMessage   string  `json:"errorMessage"`
+		} `json:"data"`
+	}
+	err := fetchCachedJSON(fmt.Sprintf("quote:mastercard:%s:%s", from, to),
+		fmt.Sprintf("https://www.mastercard.us/settlement/currencyrate/conversion-rate?fxDate=0000-00-00&transCurr=%s&crdhldBillCurr=%s&bankFee=0&transAmt=%g",
+			to, from, amount), quoteCacheTTL,
+		func(body []byte) error {
+			if err := json.Unmarshal(body, &response); err != nil {
+				return fmt.Errorf("failed to parse JSON response: %v", err)
+			}
+			if response.Data.ErrorMessage != "" {
+				return fmt.Errorf("Mastercard: %s", response.Data.ErrorMessage)
+			}
+			if response.Data.ConversionRate <= 0 {
+				return fmt.Errorf("no Mastercard rate for %s/%s", from, to)
+			}
+			return nil
+		})
+	if err != nil {
+		return 0, 0, err
+	}
+	return 1 / response.Data.ConversionRate, 0, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Config holds settings read from the user's config file. The file uses a
+// flat "key: value" format with one setting per line; blank lines and lines
+// starting with # are ignored.
+type Config struct {
+	path   string
+	values map[string]string
+}
+
+// config is the configuration loaded at startup. It is empty when no config
+// file exists.
+var config = &Config{values: make(map[string]string)}
+
+// configPath returns the location of the config file. NOMAD_CONFIG
+// overrides the default of <user config dir>/nomad/config.yaml.
+func configPath() (string, error) {
+	if path := os.Getenv("NOMAD_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %v", err)
+	}
+	return filepath.Join(dir, "nomad", "config.yaml"), nil
+}
+
+// loadConfig reads the config file. A missing file is not an error.
+func loadConfig() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{path: path, values: make(map[string]string)}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected 'key: value'", path, lineNumber)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		cfg.values[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	return cfg, nil
+}
+
+// Get returns the value for key, or "" if it is not set.
+func (c *Config) Get(key string) string {
+	return c.values[key]
+}
+
+// Bool returns the value for key interpreted as a boolean. Unset or
+// unparsable values are false.
+func (c *Config) Bool(key string) bool {
+	b, _ := strconv.ParseBool(c.values[key])
+	return b
+}
@@ -0,0 +1,557 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ExchangeRateResponse struct {
+	Rates map[string]float64 `json:"rates"`
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+}
+
+func handleCurrencyConversion(args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "trend":
+			handleTrend(args[1:])
+			return
+		case "watch":
+			handleWatch(args[1:])
+			return
+		case "table":
+			handleRateTable(args[1:])
+			return
+		case "history":
+			handleHistory(args[1:])
+			return
+		case "budget":
+			handleBudget(args[1:])
+			return
+		case "info":
+			handleCurrencyInfo(args[1:])
+			return
+		case "basket":
+			handleBasket(args[1:])
+			return
+		case "compare":
+			handleCompare(args[1:])
+			return
+		case "ppp":
+			handlePPP(args[1:])
+			return
+		case "digest":
+			handleDigest(args[1:])
+			return
+		}
+	}
+
+	args, fromClipboard := popFlag(args, "--clip")
+	args, copyResult := popFlag(args, "--copy")
+	args, cardPath, _ := popFlagValue(args, "--card")
+	args, inverse := popFlag(args, "--inverse")
+	args, feeStr, feeSet := popFlagValue(args, "--fee")
+	args, via, viaSet := popFlagValue(args, "--via")
+	args, cash := popFlag(args, "--cash")
+	args, precisionStr, precisionSet := popFlagValue(args, "--precision")
+	args, rounding, _ := popFlagValue(args, "--round")
+	args, jsonOutput := popFlag(args, "--json")
+	options.JSON = jsonOutput
+	snapshotFallback = true
+	if viaSet {
+		preferredCrossBase = mustResolveCurrency(via)
+	}
+
+	if precisionSet {
+		precision, err := strconv.Atoi(precisionStr)
+		if err != nil || precision < 0 || precision > 8 {
+			printError("Error: Invalid precision '%s' (use 0 to 8 decimals)\n", precisionStr)
+			os.Exit(1)
+		}
+		amountPrecision = precision
+	}
+	switch amountRounding = strings.ToLower(rounding); amountRounding {
+	case "", "up", "down", "bank":
+	default:
+		printError("Error: Unknown rounding '%s' (use up, down or bank)\n", rounding)
+		os.Exit(1)
+	}
+
+	var fee Fee
+	if feeSet {
+		var err error
+		if fee, err = parseFee(feeStr); err != nil {
+			printError("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var amount float64
+	var fromCurrency, toCurrency string
+	if fromClipboard {
+		amount, fromCurrency, toCurrency = parseClipboardConversion(args)
+	} else {
+		amount, fromCurrency, toCurrency = parseConversionArgs(args)
+	}
+
+	// Let the user pick any currency that was left out
+	if fromCurrency == "" {
+		fromCurrency = mustPick(pickCurrency("Convert from", ""))
+	}
+	if toCurrency == "" {
+		toCurrency = mustPick(pickCurrency(fmt.Sprintf("Convert %s to", fromCurrency), ""))
+	}
+
+	// Validate currencies
+	if !isCurrencyCode(fromCurrency) || !isCurrencyCode(toCurrency) {
+		printError("Error: Currency codes must be 3 letters (e.g., USD, EUR, THB, AUD) or a supported crypto (e.g., BTC, ETH, USDT)\n")
+		os.Exit(1)
+	}
+
+	// Get exchange rate with loading spinner
+	var rate float64
+	err := WithSpinner("Fetching exchange rates...", func() error {
+		var fetchErr error
+		rate, fetchErr = getExchangeRate(fromCurrency, toCurrency)
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error getting exchange rate: %v\n", err)
+		os.Exit(1)
+	}
+
+	conversion := Conversion{Amount: amount, From: fromCurrency, To: toCurrency, Rate: rate,
+		Provider: rateSource, Via: crossRateVia, Time: time.Now()}
+	if feeSet {
+		conversion.Fee = &fee
+	}
+	convertedAmount := conversion.Converted()
+	recordConversion(conversion)
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(conversion, "", "  ")
+		if err != nil {
+			printError("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	amountText, convertedText := formatAmount(amount, fromCurrency), formatAmount(convertedAmount, toCurrency)
+	announceResult("%s %s is %s %s", amountText, fromCurrency, convertedText, toCurrency)
+
+	// Display result with better formatting
+	fmt.Println()
+	printTitle("%s Currency Conversion\n", iconCurrency(""))
+	fmt.Printf("  %s %s %s = %s %s\n", padRight(iconSuccess(""), 3), amountText, fromCurrency, convertedText, toCurrency)
+	rateLines := conversion.RateLines(inverse)
+	for _, line := range rateLines {
+		fmt.Printf("  %s %s\n", padRight(iconInfo(""), 3), line)
+	}
+	if conversion.Via != "" {
+		fmt.Printf("  %s %s\n", padRight(iconInfo(""), 3), colorCyan(fmt.Sprintf("Derived rate: no direct %s/%s quote, calculated through %s", fromCurrency, toCurrency, conversion.Via)))
+	}
+
+	card := NewCard("Currency Conversion").
+		Add(amountText+" "+fromCurrency, convertedText+" "+toCurrency).
+		Add("Rate", rateLines[0]).
+		Add("", rateLines[1])
+
+	if feeSet {
+		effective := conversion.WithFee()
+		effectiveText := formatAmount(effective, toCurrency)
+		feeLabel := fee.String()
+		if !fee.Percent {
+			feeLabel += " " + fromCurrency
+		}
+		// A flat fee on nothing has no effective rate
+		effectiveRate := ""
+		if amount > 0 {
+			effectiveRate = " " + colorCyan(fmt.Sprintf("(effective 1 %s = %s %s)", fromCurrency, formatRate(effective/amount), toCurrency))
+		}
+		fmt.Printf("  %s With %s fee: %s %s%s\n", padRight(iconCurrency(""), 3), feeLabel, colorYellow(effectiveText), toCurrency, effectiveRate)
+		card.Add("With "+feeLabel+" fee", effectiveText+" "+toCurrency)
+	}
+	if cash {
+		if breakdown := printCashBreakdown(convertedAmount, rate, fromCurrency, toCurrency); breakdown != "" {
+			card.Add("Cash", breakdown)
+		}
+	}
+	printSnapshotNotice()
+	addRateSource()
+	printSources()
+
+	if copyResult {
+		if err := WriteClipboard(convertedText); err != nil {
+			printError("Error: %v\n", err)
+			os.Exit(1)
+		}
+		printSuccess("  Copied %s to the clipboard\n", convertedText)
+	}
+
+	saveCard(card, cardPath)
+}
+
+// Conversion is the result of converting an amount between two currencies.
+type Conversion struct {
+	Amount float64
+	From   string
+	To     string
+	Rate   float64 // units of To per unit of From
+	// Provider answered the rate lookup, "snapshot" when offline
+	Provider string
+	// Via is the currency a cross rate was derived through, if any
+	Via  string
+	Fee  *Fee
+	Time time.Time
+}
+
+// Converted returns the amount in the target currency.
+func (c Conversion) Converted() float64 {
+	return c.Amount * c.Rate
+}
+
+// WithFee returns what the amount costs in the target currency once the
+// fee is added.
+func (c Conversion) WithFee() float64 {
+	if c.Fee == nil {
+		return c.Converted()
+	}
+	return c.Fee.Apply(c.Amount) * c.Rate
+}
+
+// MarshalJSON is the object printed by `nomad cv --json`.
+func (c Conversion) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Amount    float64  `json:"amount"`
+		From      string   `json:"from"`
+		To        string   `json:"to"`
+		Rate      float64  `json:"rate"`
+		Converted float64  `json:"converted"`
+		WithFee   *float64 `json:"with_fee,omitempty"`
+		Provider  string   `json:"provider"`
+		Via       string   `json:"via,omitempty"`
+		Timestamp string   `json:"timestamp"`
+	}{
+		Amount:    c.Amount,
+		From:      c.From,
+		To:        c.To,
+		Rate:      c.Rate,
+		Converted: roundAmount(c.Converted(), amountDecimals(c.To), amountRounding),
+		Provider:  c.Provider,
+		Via:       c.Via,
+		Timestamp: c.Time.Format(time.RFC3339),
+	}
+	if c.Fee != nil {
+		withFee := roundAmount(c.WithFee(), amountDecimals(c.To), amountRounding)
+		out.WithFee = &withFee
+	}
+	return json.Marshal(out)
+}
+
+// RateLines returns the rate in both directions, the preferred orientation
+// first. Pairs listed in pinned_pairs (e.g. "thb/usd, eur/gbp") keep that
+// orientation whichever way round they are converted; inverse flips it.
+func (c Conversion) RateLines(inverse bool) []string {
+	forward := fmt.Sprintf("1 %s = %s %s", c.From, formatRate(c.Rate), c.To)
+	backward := fmt.Sprintf("1 %s = %s %s", c.To, formatRate(1/c.Rate), c.From)
+
+	if isPinnedPair(c.To, c.From) != inverse {
+		return []string{backward, forward}
+	}
+	return []string{forward, backward}
+}
+
+// Fee is a card surcharge or ATM fee charged on top of the mid-market rate,
+// either a percentage of the amount or a fixed sum in the source currency.
+type Fee struct {
+	Percent bool
+	Value   float64
+}
+
+// parseFee reads "2.5%" as a percentage and "3.50" as a fixed fee.
+func parseFee(s string) (Fee, error) {
+	text := strings.TrimSpace(s)
+	percent := strings.HasSuffix(text, "%")
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(text, "%")), 64)
+	if err != nil || value < 0 {
+		return Fee{}, fmt.Errorf("invalid fee '%s' (use a percentage like 2.5%% or an amount like 3.50)", s)
+	}
+	return Fee{Percent: percent, Value: value}, nil
+}
+
+// Apply returns amount with the fee added.
+func (f Fee) Apply(amount float64) float64 {
+	if f.Percent {
+		return amount * (1 + f.Value/100)
+	}
+	return amount + f.Value
+}
+
+func (f Fee) String() string {
+	text := strconv.FormatFloat(f.Value, 'f', -1, 64)
+	if f.Percent {
+		return text + "%"
+	}
+	return text
+}
+
+// isPinnedPair reports whether base/quote is a pinned orientation.
+func isPinnedPair(base, quote string) bool {
+	for _, pair := range strings.Split(config.Get("pinned_pairs"), ",") {
+		if strings.EqualFold(strings.TrimSpace(pair), base+"/"+quote) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatRate shows four decimals, or four significant digits for rates
+// below 0.01 so weak currencies (1 VND = 0.00003940 USD) stay readable.
+func formatRate(rate float64) string {
+	if rate >= 0.01 || rate == 0 {
+		return fmt.Sprintf("%.4f", rate)
+	}
+	digits := 4 - int(math.Floor(math.Log10(rate))) - 1
+	return strconv.FormatFloat(rate, 'f', digits, 64)
+}
+
+// formatGrouped rounds large rates to whole units with thousands separators
+// ("25,378"), falling back to formatRate for smaller ones.
+func formatGrouped(rate float64) string {
+	if rate < 100 {
+		return formatRate(rate)
+	}
+
+	digits := strconv.FormatFloat(math.Round(rate), 'f', 0, 64)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	return grouped.String()
+}
+
+// parseConversionArgs reads "<amount> [from] [to]" from the command line.
+// Currencies left out come from default_from and default_to in the config,
+// or are returned empty so they can be picked.
+func parseConversionArgs(args []string) (float64, string, string) {
+	if len(args) < 1 {
+		printConversionUsage()
+	}
+
+	// Parse command line arguments
+	amountStr := args[0]
+	fromCurrency := strings.ToUpper(config.Get("default_from"))
+	toCurrency := strings.ToUpper(config.Get("default_to"))
+	if len(args) > 1 {
+		fromCurrency = mustResolveCurrency(args[1])
+	}
+	if len(args) > 2 {
+		toCurrency = mustResolveCurrency(args[2])
+	}
+
+	// Without a target, convert to the money where the user is
+	if toCurrency == "" && fromCurrency != "" {
+		toCurrency = localTargetCurrency(fromCurrency)
+	}
+
+	// Currencies can be chosen interactively, but the amount is always required
+	if (fromCurrency == "" || toCurrency == "") && !isInteractive() {
+		printConversionUsage()
+	}
+
+	// The amount may be a sum such as "1200+450*3"
+	amount, err := evalExpression(amountStr)
+	if err != nil {
+		printError("Error: Invalid amount '%s': %v\n", amountStr, err)
+		os.Exit(1)
+	}
+
+	return amount, fromCurrency, toCurrency
+}
+
+// localTargetCurrency returns the local currency at the user's IP-based
+// location, or their home currency when that is what they're converting
+// from. It returns "" when the location or its currency is unknown.
+func localTargetCurrency(from string) string {
+	var country string
+	err := WithSpinner("Detecting location...", func() error {
+		var lookupErr error
+		country, lookupErr = currentCountry()
+		return lookupErr
+	})
+	if err != nil {
+		logVerbose("location lookup failed: %v", err)
+		return ""
+	}
+
+	local := currencyForCountry(country)
+	if local == "" {
+		return ""
+	}
+	if local == from {
+		return strings.ToUpper(config.Get("home_currency"))
+	}
+	if !options.JSON {
+		printInfo("Converting to %s, the local currency in %s\n", local, country)
+	}
+	return local
+}
+
+// mustResolveCurrency resolves a currency code, symbol or name, exiting
+// with a suggestion when it is not recognised.
+func mustResolveCurrency(input string) string {
+	// In a terminal, a name shared by several currencies is worth asking
+	// about rather than guessing
+	if choices := currencyChoices(input); len(choices) > 1 && isInteractive() {
+		return mustPick(pickCurrencyFrom(fmt.Sprintf("Which %s?", strings.TrimSpace(input)), choices))
+	}
+
+	code, err := resolveCurrency(input)
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return code
+}
+
+func printConversionUsage() {
+	printError("Usage: nomad cv <amount> <from_currency> <to_currency>\n")
+	printInfo("Example: nomad cv 1000 thb aud\n")
+	printInfo("Example: nomad cv \"1200+450*3\" thb aud\n")
+	printInfo("Example: nomad cv --clip [to_currency]\n")
+	printInfo("Example: nomad cv 200 usd thb --cash\n")
+	printInfo("Example: nomad cv 100 usd thb --json\n")
+	os.Exit(1)
+}
+
+// parseClipboardConversion reads a price such as "€1.299,00" from the
+// clipboard. The target currency is the optional argument, falling back to
+// home_currency from the config.
+func parseClipboardConversion(args []string) (float64, string, string) {
+	text, err := ReadClipboard()
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	amount, fromCurrency, err := parsePrice(text)
+	if err != nil {
+		printError("Error: Could not read a price from the clipboard: %v\n", err)
+		os.Exit(1)
+	}
+
+	toCurrency := strings.ToUpper(config.Get("home_currency"))
+	if len(args) > 0 {
+		toCurrency = mustResolveCurrency(args[0])
+	}
+
+	if !options.JSON {
+		printInfo("From clipboard: %s\n", text)
+	}
+	return amount, fromCurrency, toCurrency
+}
+
+func getExchangeRate(fromCurrency, toCurrency string) (float64, error) {
+	// The provenance describes this lookup only, not one made earlier in
+	// the run such as the country hint's
+	crossRateVia, rateSource, rateDate = "", "", ""
+	rateFallbackReason, snapshotUsed = "", ""
+	if isCrypto(fromCurrency) || isCrypto(toCurrency) {
+		return getCryptoRate(fromCurrency, toCurrency)
+	}
+
+	rates, err := getExchangeRates(fromCurrency)
+	if err == nil {
+		if rate, exists := rates.Rates[toCurrency]; exists {
+			return rate, nil
+		}
+		err = fmt.Errorf("currency '%s' not found in exchange rates", toCurrency)
+	}
+
+	// Exotic pairs may only be quoted against the majors
+	if rate, via, ok := crossRate(fromCurrency, toCurrency); ok {
+		crossRateVia = via
+		return rate, nil
+	}
+	return 0, err
+}
+
+var (
+	// preferredCrossBase is the currency chosen with --via to derive
+	// cross rates through, overriding cross_via in the config
+	preferredCrossBase string
+	// crossRateVia is the currency the last rate was derived through, when
+	// the provider did not quote the pair directly
+	crossRateVia string
+	// rateSource is the provider that answered the last rate lookup, and
+	// rateDate the date its rates were published
+	rateSource string
+	rateDate   string
+)
+
+// crossRate computes from/to through the preferred base currency, then USD
+// and EUR.
+func crossRate(from, to string) (float64, string, bool) {
+	preferred := preferredCrossBase
+	if preferred == "" {
+		preferred = strings.ToUpper(config.Get("cross_via"))
+	}
+
+	var bases []string
+	for _, base := range []string{preferred, "USD", "EUR"} {
+		if base != "" && base != from && base != to && !containsString(bases, base) {
+			bases = append(bases, base)
+		}
+	}
+
+	for _, base := range bases {
+		rates, err := getExchangeRates(base)
+		if err != nil {
+			continue
+		}
+		fromRate, fromOK := rates.Rates[from]
+		toRate, toOK := rates.Rates[to]
+		if fromOK && toOK && fromRate > 0 {
+			logVerbose("no direct %s/%s rate, derived through %s", from, to, base)
+			return toRate / fromRate, base, true
+		}
+	}
+	return 0, "", false
+}
+
+// isCurrencyCode reports whether code looks like an ISO currency code or is
+// a supported cryptocurrency.
+func isCurrencyCode(code string) bool {
+	return len(code) == 3 || isCrypto(code)
+}
+
+// getExchangeRates fetches the rates of every currency against base from
+// the configured rate provider, then the other providers in turn, falling
+// back to the built-in snapshot when the command allows it.
+func getExchangeRates(base string) (*ExchangeRateResponse, error) {
+	rates, source, err := latestFromChain(base)
+	rateSource = source
+	if err == nil {
+		rateDate = rates.Date
+	}
+	if err != nil && source != "" && snapshotFallback {
+		if snapshot, snapshotErr := snapshotRates(base); snapshotErr == nil {
+			logVerbose("%s failed (%v), using the %s snapshot", source, err, snapshot.Date)
+			snapshotUsed = snapshot.Date
+			rateSource, rateDate = "snapshot", snapshot.Date
+			return snapshot, nil
+		}
+	}
+	return rates, err
+}
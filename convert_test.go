@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"testing"
+)
+
+// useMock serves requests from the bundled mock data for one test.
+func useMock(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	saved := options
+	options.Mock = true
+	replayer = mockTransport{}
+	clientTransportOnce = sync.Once{}
+	t.Cleanup(func() {
+		options = saved
+		replayer = nil
+		clientTransportOnce = sync.Once{}
+	})
+}
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-out
+}
+
+func TestConvertJSONWithoutTarget(t *testing.T) {
+	useMock(t)
+
+	// The target is the local currency, found from the IP-based location
+	out := captureStdout(t, func() { handleCurrencyConversion([]string{"100", "usd", "--json"}) })
+
+	var conversion struct {
+		Amount    float64 `json:"amount"`
+		From      string  `json:"from"`
+		To        string  `json:"to"`
+		Converted float64 `json:"converted"`
+	}
+	if err := json.Unmarshal([]byte(out), &conversion); err != nil {
+		t.Fatalf("stdout is not a JSON object: %v\n%s", err, out)
+	}
+	if conversion.Amount != 100 || conversion.From != "USD" || conversion.To == "" || conversion.Converted <= 0 {
+		t.Errorf("unexpected conversion %+v", conversion)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import "strings"
+
+// Country is an entry in the offline country dataset.
+type Country struct {
+	Code    string // ISO 3166-1 alpha-2
+	Name    string
+	Aliases []string
+	Drive   DrivingRules
+	Safety  FoodSafety
+}
+
+// DrivingRules are the road rules a visiting driver needs to know.
+type DrivingRules struct {
+	Side string // "left" or "right"
+	// IDP says whether visitors with a foreign licence need an
+	// International Driving Permit
+	IDP string
+	// Speed limits in km/h in town, on rural roads and on motorways; 0 on
+	// motorways means no general limit
+	Urban, Rural, Motorway int
+	// BAC is the blood alcohol limit in percent; 0 means zero tolerance
+	BAC   float64
+	Notes string
+}
+
+// FoodSafety is quick guidance on tap water, ice and food.
+type FoodSafety struct {
+	// Water is "safe", "caution" or "unsafe" to drink from the tap
+	Water     string
+	WaterNote string
+	Ice       string
+	Food      string
+}
+
+// countries lists popular nomad destinations.
+var countries = []Country{
+	{"AE", "United Arab Emirates", []string{"uae", "dubai"}, DrivingRules{"right",
+		"Required unless your licence is from the GCC, EU, UK, US and some others", 60, 100, 120, 0,
+		"Zero tolerance for alcohol; speed cameras everywhere"},
+		FoodSafety{"safe", "Safe, though desalinated and most people drink bottled", "Safe", "High hygiene standards"}},
+	{"AR", "Argentina", nil, DrivingRules{"right",
+		"Required", 60, 110, 130, 0.05,
+		"Headlights on at all times outside towns"},
+		FoodSafety{"safe", "Safe in Buenos Aires and most cities", "Safe", "Generally safe"}},
+	{"AU", "Australia", nil, DrivingRules{"left",
+		"Required if your licence is not in English", 50, 100, 110, 0.05,
+		"Limits vary by state; watch for wildlife at dusk"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"BR", "Brazil", nil, DrivingRules{"right",
+		"Recommended, with your licence, for up to 180 days", 50, 80, 110, 0,
+		"Zero tolerance for alcohol"},
+		FoodSafety{"caution", "Drink filtered or bottled water", "Usually made from filtered water in restaurants", "Street food is fine where it is busy; avoid undercooked seafood"}},
+	{"CA", "Canada", nil, DrivingRules{"right",
+		"Recommended; needed in some provinces if your licence is not in English or French", 50, 80, 100, 0.08,
+		"Provinces suspend licences from 0.05%"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"CO", "Colombia", nil, DrivingRules{"right",
+		"Not needed for stays under 6 months", 50, 90, 120, 0.02,
+		"Pico y placa restricts driving by plate number in big cities"},
+		FoodSafety{"caution", "Safe in Bogota and Medellin; bottled elsewhere", "Fine in cities", "Wash or peel fruit"}},
+	{"DE", "Germany", nil, DrivingRules{"right",
+		"Not needed for EU licences; others need a translation or IDP, valid 6 months", 50, 100, 0, 0.05,
+		"No general Autobahn limit, 130 km/h advised; 0.0% for new drivers"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"ES", "Spain", nil, DrivingRules{"right",
+		"Not needed for EU licences; required for most others", 50, 90, 120, 0.05,
+		"Carry two warning triangles or a V16 beacon"},
+		FoodSafety{"safe", "Safe, though the taste varies", "Safe", "High hygiene standards"}},
+	{"FR", "France", nil, DrivingRules{"right",
+		"Not needed for EU licences; recommended for others, valid 1 year", 50, 80, 130, 0.05,
+		"110 km/h on motorways in rain"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"GB", "United Kingdom", []string{"uk", "england", "scotland", "britain"}, DrivingRules{"left",
+		"Not needed for most licences for up to 12 months", 48, 96, 112, 0.08,
+		"Limits are signed in mph (30/60/70); Scotland's BAC limit is 0.05%"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"GE", "Georgia", nil, DrivingRules{"right",
+		"Not needed; foreign licences are valid for a year", 60, 90, 110, 0.03,
+		"Mountain roads close in winter"},
+		FoodSafety{"caution", "Generally safe in Tbilisi; many prefer bottled", "Safe in restaurants", "Generally safe"}},
+	{"GR", "Greece", nil, DrivingRules{"right",
+		"Not needed for EU licences; required for most others", 50, 90, 130, 0.05,
+		"Renting a scooter also needs a motorcycle licence"},
+		FoodSafety{"caution", "Safe on the mainland; bottled on many islands", "Safe", "Generally safe"}},
+	{"HR", "Croatia", nil, DrivingRules{"right",
+		"Not needed for EU licences; recommended for others", 50, 90, 130, 0.05,
+		"Dipped headlights on from late October to late March"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"ID", "Indonesia", []string{"bali"}, DrivingRules{"left",
+		"Required, with a motorcycle category to ride a scooter", 50, 80, 100, 0,
+		"No set alcohol limit; police checks for scooter licences are common in Bali"},
+		FoodSafety{"unsafe", "Drink bottled or refilled water", "Factory tube ice with a hole is safe; avoid crushed block ice", "Bali belly is common: pick busy warungs and skip raw salads"}},
+	{"IN", "India", nil, DrivingRules{"left",
+		"Required", 50, 70, 120, 0.03,
+		"Limits vary widely by state"},
+		FoodSafety{"unsafe", "Drink sealed bottled or filtered water", "Avoid unless made from filtered water", "Eat freshly cooked hot food; skip raw salads and cut fruit from stalls"}},
+	{"IT", "Italy", nil, DrivingRules{"right",
+		"Not needed for EU licences; required for others", 50, 90, 130, 0.05,
+		"ZTL zones in old towns fine unregistered cars automatically"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"JP", "Japan", nil, DrivingRules{"left",
+		"Required (1949 convention); licences from Germany, France, Switzerland, Belgium, Monaco and Taiwan need an official translation instead", 40, 60, 100, 0.03,
+		"Passengers can be fined for riding with a drunk driver"},
+		FoodSafety{"safe", "", "Safe", "Very high standards, including raw fish at reputable places"}},
+	{"KR", "South Korea", []string{"korea"}, DrivingRules{"right",
+		"Required", 50, 80, 110, 0.03,
+		"Right turn on red only after a full stop"},
+		FoodSafety{"safe", "Safe, though most people drink filtered", "Safe", "High hygiene standards"}},
+	{"MX", "Mexico", nil, DrivingRules{"right",
+		"Not needed; foreign licences are accepted", 50, 80, 110, 0.08,
+		"Some states are stricter; avoid driving at night on rural roads"},
+		FoodSafety{"unsafe", "Drink bottled or purified water", "Usually purified in restaurants and bars", "Busy street stalls are fine; avoid salads washed in tap water"}},
+	{"MY", "Malaysia", nil, DrivingRules{"left",
+		"Recommended; required if your licence is not in English or Malay", 60, 90, 110, 0.08,
+		"Helmets are mandatory on motorcycles"},
+		FoodSafety{"caution", "Treated, but old pipes vary; boil or filter", "Generally safe in restaurants", "Hawker food is generally safe"}},
+	{"NL", "Netherlands", []string{"holland"}, DrivingRules{"right",
+		"Not needed for EU licences; others are valid for 185 days", 50, 80, 100, 0.05,
+		"Cyclists usually have priority; 100 km/h on motorways by day"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"NZ", "New Zealand", nil, DrivingRules{"left",
+		"Required if your licence is not in English", 50, 100, 110, 0.05,
+		"Zero limit for drivers under 20"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"PH", "Philippines", nil, DrivingRules{"right",
+		"Not needed for the first 90 days", 40, 80, 100, 0.05,
+		"Number coding restricts driving days in Metro Manila"},
+		FoodSafety{"unsafe", "Drink bottled or purified water", "Fine in restaurants; avoid it at small stalls", "Choose freshly cooked food"}},
+	{"PT", "Portugal", nil, DrivingRules{"right",
+		"Not needed for EU licences; required for most others", 50, 90, 120, 0.05,
+		"Motorway tolls are often electronic only"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"SG", "Singapore", nil, DrivingRules{"left",
+		"Required if your licence is not in English; foreign licences valid 12 months", 50, 70, 90, 0.08,
+		"ERP road pricing applies in the city"},
+		FoodSafety{"safe", "", "Safe", "Hawker stalls display a hygiene grade from A to D"}},
+	{"TH", "Thailand", nil, DrivingRules{"left",
+		"Required, except for ASEAN licences; a motorcycle category is needed to ride a scooter", 80, 90, 120, 0.05,
+		"Helmets are mandatory; police checkpoints for scooter licences are common"},
+		FoodSafety{"unsafe", "Drink bottled water or use refill stations", "Factory tube ice with a hole is safe", "Street food is generally safe where it is busy and cooked to order"}},
+	{"TR", "Turkey", []string{"turkiye"}, DrivingRules{"right",
+		"Not needed for 6 months; recommended if your licence is not in Latin script", 50, 90, 120, 0.05,
+		"HGS toll sticker needed on motorways"},
+		FoodSafety{"caution", "Treated, but most people drink bottled", "Safe in restaurants", "Generally safe; careful with undercooked meat"}},
+	{"US", "United States", []string{"usa", "america"}, DrivingRules{"right",
+		"Recommended; required by a few states and rental companies", 40, 90, 113, 0.08,
+		"Limits are signed in mph and set by each state; Utah's BAC limit is 0.05%"},
+		FoodSafety{"safe", "", "Safe", "High hygiene standards"}},
+	{"VN", "Vietnam", nil, DrivingRules{"right",
+		"Required (1968 convention only); most other IDPs and foreign licences are not valid", 50, 80, 120, 0,
+		"Zero tolerance for alcohol; most rental scooters need an A1 licence"},
+		FoodSafety{"unsafe", "Drink bottled or filtered water", "Factory ice in cylinders is safe; avoid crushed block ice at stalls", "Street food is generally safe where it is busy; wash raw herbs"}},
+	{"ZA", "South Africa", nil, DrivingRules{"left",
+		"Required if your licence is not in English or has no photo", 60, 100, 120, 0.05,
+		"Four-way stops are first come, first go"},
+		FoodSafety{"safe", "Safe in cities", "Safe", "Generally safe"}},
+}
+
+// euCountries are the EU and EEA members, whose licences are valid across
+// the EU without an International Driving Permit.
+var euCountries = []string{
+	"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "ES", "FI", "FR", "GR", "HR", "HU",
+	"IE", "IS", "IT", "LI", "LT", "LU", "LV", "MT", "NL", "NO", "PL", "PT", "RO", "SE", "SI", "SK",
+}
+
+// findCountry looks up a country by ISO code, name or alias.
+func findCountry(name string) *Country {
+	name = strings.TrimSpace(name)
+	for i := range countries {
+		country := &countries[i]
+		if strings.EqualFold(country.Code, name) || strings.EqualFold(country.Name, name) {
+			return country
+		}
+		for _, alias := range country.Aliases {
+			if strings.EqualFold(alias, name) {
+				return country
+			}
+		}
+	}
+	return nil
+}
+
+// countryNames returns the names of every country in the dataset.
+func countryNames() []string {
+	names := make([]string, len(countries))
+	for i, country := range countries {
+		names[i] = country.Name
+	}
+	return names
+}
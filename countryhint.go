@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const locationStateFile = "location.json"
+
+// locationState remembers the place and country last reported by an
+// IP-based lookup and the country the user was last told about.
+type locationState struct {
+	// Place is "City, Country", "" when the lookup only gave the country
+	Place     string    `json:"place,omitempty"`
+	Country   string    `json:"country"`
+	SeenAt    time.Time `json:"seenAt"`
+	Announced string    `json:"announced"`
+}
+
+// rememberCountry records the country from an IP-based location lookup so
+// the next command can mention a border crossing.
+func rememberCountry(country string) {
+	rememberPlace("", country)
+}
+
+// rememberPlace records the place and country from an IP-based location
+// lookup, so the place is known while wttr.in is down.
+func rememberPlace(place, country string) {
+	if country == "" || options.Mock {
+		return
+	}
+
+	var state locationState
+	if err := loadJSON(locationStateFile, &state); err != nil {
+		return
+	}
+	// The first country ever seen is home base, not news
+	if state.Announced == "" {
+		state.Announced = country
+	}
+	if place != "" || country != state.Country {
+		state.Place = place
+	}
+	state.Country = country
+	state.SeenAt = time.Now()
+	saveJSON(locationStateFile, state)
+}
+
+// locationFreshness is how long a remembered country is trusted before the
+// IP-based lookup is repeated.
+const locationFreshness = time.Hour
+
+// currentCountry returns the country the user is in, from a recent lookup
+// or a fresh IP-based one through the weather provider.
+func currentCountry() (string, error) {
+	var state locationState
+	if err := loadJSON(locationStateFile, &state); err == nil && state.Country != "" &&
+		time.Since(state.SeenAt) < locationFreshness && !options.Mock {
+		return state.Country, nil
+	}
+
+	response, err := wttr.Forecast("")
+	if err != nil {
+		return "", err
+	}
+	area, ok := response.Area()
+	if !ok {
+		return "", fmt.Errorf("no location in the weather response")
+	}
+	country := area.Country.String()
+	if country == "" {
+		return "", fmt.Errorf("no country in the weather response")
+	}
+	rememberCountry(country)
+	return country, nil
+}
+
+// showCountryHint prints a one-time note with the local currency when the
+// last known country differs from the one the user was last told about.
+func showCountryHint() {
+	if options.Mock {
+		return
+	}
+
+	var state locationState
+	if err := loadJSON(locationStateFile, &state); err != nil || state.Country == "" {
+		return
+	}
+	if strings.EqualFold(state.Country, state.Announced) {
+		return
+	}
+
+	state.Announced = state.Country
+	if err := saveJSON(locationStateFile, state); err != nil {
+		return
+	}
+	defer showArrivalWaterNote(state.Country)
+
+	local := currencyForCountry(state.Country)
+	if local == "" {
+		printInfo("Looks like you're now in %s\n", state.Country)
+		return
+	}
+
+	home := strings.ToUpper(config.Get("home_currency"))
+	if home == "" {
+		home = "USD"
+	}
+	if home == local {
+		printInfo("Looks like you're now in %s — local currency %s\n", state.Country, local)
+		return
+	}
+
+	// A failed rate lookup only drops the rate from the hint
+	rate, err := getExchangeRate(home, local)
+	if err != nil {
+		printInfo("Looks like you're now in %s — local currency %s\n", state.Country, local)
+		return
+	}
+	printInfo("Looks like you're now in %s — local currency %s; 1 %s ≈ %s %s\n",
+		state.Country, local, home, formatGrouped(rate), local)
+}
+
+// showArrivalWaterNote adds whether tap water is drinkable to the arrival
+// hint, for countries in the dataset.
+func showArrivalWaterNote(name string) {
+	if country := findCountry(name); country != nil {
+		printInfo("Tap water: %s\n", tapWaterSummary(country.Safety))
+	}
+}
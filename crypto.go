@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// cryptoAssets maps ticker symbols to CoinGecko coin IDs.
+var cryptoAssets = map[string]string{
+	"BTC":  "bitcoin",
+	"ETH":  "ethereum",
+	"USDT": "tether",
+	"USDC": "usd-coin",
+	"SOL":  "solana",
+	"BNB":  "binancecoin",
+	"XRP":  "ripple",
+	"ADA":  "cardano",
+	"DOGE": "dogecoin",
+	"LTC":  "litecoin",
+	"DOT":  "polkadot",
+	"TRX":  "tron",
+	"XMR":  "monero",
+}
+
+// cryptoCacheTTL is how long crypto prices are reused; they move far faster
+// than fiat rates.
+const cryptoCacheTTL = time.Minute
+
+// isCrypto reports whether code is a supported cryptocurrency.
+func isCrypto(code string) bool {
+	_, ok := cryptoAssets[strings.ToUpper(code)]
+	return ok
+}
+
+// getCryptoRate converts between any mix of crypto and fiat currencies by
+// pricing both sides in USD.
+func getCryptoRate(from, to string) (float64, error) {
+	fromUSD, err := usdValue(from)
+	if err != nil {
+		return 0, err
+	}
+	toUSD, err := usdValue(to)
+	if err != nil {
+		return 0, err
+	}
+	// The fiat side, if any, was priced by the rate provider
+	if rateSource != "" && rateSource != "coingecko" {
+		rateSource = "coingecko+" + rateSource
+	} else {
+		rateSource = "coingecko"
+	}
+	rateDate = time.Now().Format("2006-01-02 15:04")
+	return fromUSD / toUSD, nil
+}
+
+// usdValue returns what one unit of code is worth in US dollars.
+func usdValue(code string) (float64, error) {
+	if id, ok := cryptoAssets[code]; ok {
+		return getCryptoPrice(id)
+	}
+
+	rates, err := getExchangeRates("USD")
+	if err != nil {
+		return 0, err
+	}
+	rate, ok := rates.Rates[code]
+	if !ok || rate == 0 {
+		return 0, fmt.Errorf("currency '%s' not found in exchange rates", code)
+	}
+	return 1 / rate, nil
+}
+
+// getCryptoPrice fetches the USD price of a coin from CoinGecko.
+func getCryptoPrice(id string) (float64, error) {
+	var price float64
+	err := fetchCachedJSON("crypto:"+id,
+		fmt.Sprintf("https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=usd", id), cryptoCacheTTL,
+		func(body []byte) error {
+			var prices map[string]map[string]float64
+			if err := json.Unmarshal(body, &prices); err != nil {
+				return fmt.Errorf("failed to parse JSON response: %v", err)
+			}
+			var ok bool
+			if price, ok = prices[id]["usd"]; !ok || price == 0 {
+				return fmt.Errorf("no price for %s", id)
+			}
+			return nil
+		})
+	if err != nil {
+		return 0, fmt.Errorf("CoinGecko: %v", err)
+	}
+	return price, nil
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Currency describes an ISO 4217 currency in the offline dataset.
+type Currency struct {
+	Code   string
+	Name   string
+	Symbol string
+	// Subunit is the hundredth of the currency, or "" when prices are
+	// only ever given in whole units
+	Subunit string
+}
+
+// Label returns the "CODE - Name" form shown in the picker.
+func (c Currency) Label() string {
+	return c.Code + " - " + c.Name
+}
+
+// currencies lists the currencies most relevant to travellers.
+var currencies = []Currency{
+	{"AED", "UAE Dirham", "د.إ", "fils"},
+	{"ARS", "Argentine Peso", "$", "centavo"},
+	{"AUD", "Australian Dollar", "A$", "cent"},
+	{"BGN", "Bulgarian Lev", "лв", "stotinka"},
+	{"BRL", "Brazilian Real", "R$", "centavo"},
+	{"CAD", "Canadian Dollar", "C$", "cent"},
+	{"CHF", "Swiss Franc", "CHF", "rappen"},
+	{"CLP", "Chilean Peso", "$", ""},
+	{"CNY", "Chinese Yuan", "¥", "fen"},
+	{"COP", "Colombian Peso", "$", ""},
+	{"CZK", "Czech Koruna", "Kč", ""},
+	{"DKK", "Danish Krone", "kr", "øre"},
+	{"EGP", "Egyptian Pound", "E£", "piastre"},
+	{"EUR", "Euro", "€", "cent"},
+	{"GBP", "British Pound", "£", "penny"},
+	{"GEL", "Georgian Lari", "₾", "tetri"},
+	{"HKD", "Hong Kong Dollar", "HK$", "cent"},
+	{"HUF", "Hungarian Forint", "Ft", ""},
+	{"IDR", "Indonesian Rupiah", "Rp", ""},
+	{"ILS", "Israeli New Shekel", "₪", "agora"},
+	{"INR", "Indian Rupee", "₹", "paisa"},
+	{"ISK", "Icelandic Krona", "kr", ""},
+	{"JPY", "Japanese Yen", "¥", ""},
+	{"KHR", "Cambodian Riel", "៛", ""},
+	{"KRW", "South Korean Won", "₩", ""},
+	{"LAK", "Lao Kip", "₭", ""},
+	{"LKR", "Sri Lankan Rupee", "Rs", "cent"},
+	{"MAD", "Moroccan Dirham", "DH", "centime"},
+	{"MXN", "Mexican Peso", "$", "centavo"},
+	{"MYR", "Malaysian Ringgit", "RM", "sen"},
+	{"NOK", "Norwegian Krone", "kr", "øre"},
+	{"NPR", "Nepalese Rupee", "Rs", "paisa"},
+	{"NZD", "New Zealand Dollar", "NZ$", "cent"},
+	{"PEN", "Peruvian Sol", "S/", "céntimo"},
+	{"PHP", "Philippine Peso", "₱", "sentimo"},
+	{"PLN", "Polish Zloty", "zł", "grosz"},
+	{"RON", "Romanian Leu", "lei", "ban"},
+	{"RSD", "Serbian Dinar", "din", ""},
+	{"SEK", "Swedish Krona", "kr", "öre"},
+	{"SGD", "Singapore Dollar", "S$", "cent"},
+	{"THB", "Thai Baht", "฿", "satang"},
+	{"TRY", "Turkish Lira", "₺", "kuruş"},
+	{"TWD", "New Taiwan Dollar", "NT$", ""},
+	{"UAH", "Ukrainian Hryvnia", "₴", "kopiyka"},
+	{"USD", "US Dollar", "$", "cent"},
+	{"VND", "Vietnamese Dong", "₫", ""},
+	{"ZAR", "South African Rand", "R", "cent"},
+}
+
+// currencyLabels returns the picker labels of all currencies in the dataset.
+func currencyLabels() []string {
+	labels := make([]string, len(currencies))
+	for i, c := range currencies {
+		labels[i] = c.Label()
+	}
+	return labels
+}
+
+// threeDecimalCurrencies are divided into thousandths rather than
+// hundredths.
+var threeDecimalCurrencies = map[string]bool{"BHD": true, "JOD": true, "KWD": true, "OMR": true, "TND": true}
+
+// currencyDecimals is how many decimals amounts in code are shown with:
+// none for currencies without a subunit in everyday use, eight for crypto.
+func currencyDecimals(code string) int {
+	code = strings.ToUpper(code)
+	switch {
+	case isCrypto(code):
+		return 8
+	case threeDecimalCurrencies[code]:
+		return 3
+	}
+	if c := findCurrency(code); c != nil && c.Subunit == "" {
+		return 0
+	}
+	return 2
+}
+
+// Amount formatting set by cv's --precision and --round flags.
+var (
+	// amountPrecision overrides the currency's decimals when 0 or more
+	amountPrecision = -1
+	// amountRounding is "up", "down", "bank" or "" to round half away
+	// from zero
+	amountRounding string
+)
+
+// amountDecimals returns how many decimals amounts in code are shown with:
+// the currency's own, unless --precision says otherwise.
+func amountDecimals(code string) int {
+	if amountPrecision >= 0 {
+		return amountPrecision
+	}
+	return currencyDecimals(code)
+}
+
+// formatAmount shows fiat amounts with their currency's usual decimals and
+// crypto amounts with up to eight, since 0.00 BTC is rarely what anyone
+// wants to see.
+func formatAmount(amount float64, code string) string {
+	decimals := amountDecimals(code)
+	s := strconv.FormatFloat(roundAmount(amount, decimals, amountRounding), 'f', decimals, 64)
+	if isCrypto(code) && amountPrecision < 0 && strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// roundAmount rounds to decimals places: up, down, to even on a tie
+// ("bank"), or otherwise half away from zero.
+func roundAmount(amount float64, decimals int, mode string) float64 {
+	scale := math.Pow(10, float64(decimals))
+	scaled := amount * scale
+	// Binary noise just off a whole number must not make up round 2.00
+	// to 2.01
+	nearest := math.Round(scaled)
+	if math.Abs(scaled-nearest) < 1e-9*math.Max(1, math.Abs(scaled)) {
+		scaled = nearest
+	}
+
+	switch mode {
+	case "up":
+		return math.Ceil(scaled) / scale
+	case "down":
+		return math.Floor(scaled) / scale
+	case "bank":
+		return math.RoundToEven(scaled) / scale
+	default:
+		return math.Round(scaled) / scale
+	}
+}
+
+// currencyCodeFromLabel extracts the ISO code from a picker label.
+func currencyCodeFromLabel(label string) string {
+	code, _, _ := strings.Cut(label, " - ")
+	return code
+}
+
+// findCurrency looks up a currency by ISO code, returning nil if it is not
+// in the dataset.
+func findCurrency(code string) *Currency {
+	code = strings.ToUpper(code)
+	for i := range currencies {
+		if currencies[i].Code == code {
+			return &currencies[i]
+		}
+	}
+	return nil
+}
+
+// countryCurrencies maps country names, as returned by the weather and
+// geocoding providers, to their local currency.
+var countryCurrencies = map[string]string{
+	"argentina":                "ARS",
+	"australia":                "AUD",
+	"austria":                  "EUR",
+	"belgium":                  "EUR",
+	"brazil":                   "BRL",
+	"bulgaria":                 "BGN",
+	"cambodia":                 "KHR",
+	"canada":                   "CAD",
+	"chile":                    "CLP",
+	"china":                    "CNY",
+	"colombia":                 "COP",
+	"croatia":                  "EUR",
+	"czech republic":           "CZK",
+	"czechia":                  "CZK",
+	"denmark":                  "DKK",
+	"egypt":                    "EGP",
+	"estonia":                  "EUR",
+	"finland":                  "EUR",
+	"france":                   "EUR",
+	"georgia":                  "GEL",
+	"germany":                  "EUR",
+	"greece":                   "EUR",
+	"hong kong":                "HKD",
+	"hungary":                  "HUF",
+	"iceland":                  "ISK",
+	"india":                    "INR",
+	"indonesia":                "IDR",
+	"ireland":                  "EUR",
+	"israel":                   "ILS",
+	"italy":                    "EUR",
+	"japan":                    "JPY",
+	"laos":                     "LAK",
+	"malaysia":                 "MYR",
+	"malta":                    "EUR",
+	"mexico":                   "MXN",
+	"morocco":                  "MAD",
+	"nepal":                    "NPR",
+	"netherlands":              "EUR",
+	"new zealand":              "NZD",
+	"norway":                   "NOK",
+	"peru":                     "PEN",
+	"philippines":              "PHP",
+	"poland":                   "PLN",
+	"portugal":                 "EUR",
+	"romania":                  "RON",
+	"serbia":                   "RSD",
+	"singapore":                "SGD",
+	"south africa":             "ZAR",
+	"south korea":              "KRW",
+	"spain":                    "EUR",
+	"sri lanka":                "LKR",
+	"sweden":                   "SEK",
+	"switzerland":              "CHF",
+	"taiwan":                   "TWD",
+	"thailand":                 "THB",
+	"turkey":                   "TRY",
+	"ukraine":                  "UAH",
+	"united arab emirates":     "AED",
+	"united kingdom":           "GBP",
+	"united states":            "USD",
+	"united states of america": "USD",
+	"vietnam":                  "VND",
+}
+
+// currencyForCountry returns the local currency of a country, or "" if it
+// is not known.
+func currencyForCountry(country string) string {
+	return countryCurrencies[strings.ToLower(strings.TrimSpace(country))]
+}
+
+// currencyAliases maps everyday names for currencies to their ISO codes.
+// Full names from the dataset ("thai baht") are matched separately.
+var currencyAliases = map[string]string{
+	"dollar":          "USD",
+	"us dollar":       "USD",
+	"buck":            "USD",
+	"greenback":       "USD",
+	"euro":            "EUR",
+	"pound":           "GBP",
+	"sterling":        "GBP",
+	"quid":            "GBP",
+	"aussie dollar":   "AUD",
+	"aussie":          "AUD",
+	"kiwi dollar":     "NZD",
+	"loonie":          "CAD",
+	"swiss franc":     "CHF",
+	"franc":           "CHF",
+	"yen":             "JPY",
+	"yuan":            "CNY",
+	"renminbi":        "CNY",
+	"baht":            "THB",
+	"dong":            "VND",
+	"rupiah":          "IDR",
+	"ringgit":         "MYR",
+	"won":             "KRW",
+	"kip":             "LAK",
+	"riel":            "KHR",
+	"lari":            "GEL",
+	"lira":            "TRY",
+	"forint":          "HUF",
+	"zloty":           "PLN",
+	"koruna":          "CZK",
+	"rand":            "ZAR",
+	"shekel":          "ILS",
+	"dirham":          "AED",
+	"rupee":           "INR",
+	"real":            "BRL",
+	"hryvnia":         "UAH",
+	"sol":             "PEN",
+	"peso":            "MXN",
+	"mexican peso":    "MXN",
+	"philippine peso": "PHP",
+}
+
+// currencyFamilies lists the currencies that share an everyday name. Where
+// currencyAliases has the name too, that is the answer outside a terminal.
+var currencyFamilies = map[string][]string{
+	"dollar": {"USD", "AUD", "CAD", "NZD", "SGD", "HKD", "TWD"},
+	"peso":   {"MXN", "ARS", "CLP", "COP", "PHP"},
+	"rupee":  {"INR", "LKR", "NPR"},
+	"pound":  {"GBP", "EGP"},
+	"dirham": {"AED", "MAD"},
+	"krone":  {"DKK", "NOK"},
+	"krona":  {"SEK", "ISK"},
+}
+
+// currencyChoices returns every currency input could mean when it is a
+// shared name such as "dollar" or a symbol such as "$", or nil when it is
+// not ambiguous.
+func currencyChoices(input string) []string {
+	text := strings.TrimSpace(input)
+	name := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if codes, ok := currencyFamilies[name]; ok {
+		return codes
+	}
+	if codes, ok := currencyFamilies[strings.TrimSuffix(name, "s")]; ok {
+		return codes
+	}
+	if codes := currencySymbols()[text]; len(codes) > 1 {
+		return codes
+	}
+	return nil
+}
+
+// resolveCurrency turns what a user typed, be it an ISO code, a symbol such
+// as "€" or "฿", or a name like "baht" or "aussie dollar", into an ISO code
+// or supported crypto ticker. Unknown three-letter codes pass through for
+// the rate provider to judge; anything else gets a did-you-mean suggestion.
+func resolveCurrency(input string) (string, error) {
+	text := strings.TrimSpace(input)
+	code := strings.ToUpper(text)
+	if findCurrency(code) != nil || isCrypto(code) {
+		return code, nil
+	}
+
+	name := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if code, ok := currencyAliases[name]; ok {
+		return code, nil
+	}
+	if code, ok := currencyAliases[strings.TrimSuffix(name, "s")]; ok {
+		return code, nil
+	}
+	if codes := currencyChoices(text); len(codes) > 1 {
+		return "", fmt.Errorf("'%s' could be %s; use the currency code", text, strings.Join(codes, ", "))
+	}
+	for _, c := range currencies {
+		if strings.EqualFold(c.Name, name) || strings.EqualFold(c.Name+"s", name) {
+			return c.Code, nil
+		}
+	}
+	for ticker, id := range cryptoAssets {
+		if id == name {
+			return ticker, nil
+		}
+	}
+
+	if codes, ok := currencySymbols()[text]; ok {
+		if len(codes) > 1 {
+			return "", fmt.Errorf("'%s' is used by %s; use the currency code", text, strings.Join(codes, ", "))
+		}
+		return codes[0], nil
+	}
+
+	if len(code) == 3 && strings.IndexFunc(code, func(r rune) bool { return r < 'A' || r > 'Z' }) < 0 {
+		return code, nil
+	}
+
+	if suggestions := suggestCurrencies(name); len(suggestions) > 0 {
+		return "", fmt.Errorf("unknown currency '%s', did you mean %s?", text, strings.Join(suggestions, " or "))
+	}
+	return "", fmt.Errorf("unknown currency '%s'", text)
+}
+
+// suggestCurrencies returns the codes whose code, name or alias is within
+// a typo or two of name, closest first.
+func suggestCurrencies(name string) []string {
+	best := make(map[string]int)
+	consider := func(candidate, code string) {
+		limit := 1
+		if len(candidate) > 5 {
+			limit = 2
+		}
+		if d := editDistance(name, strings.ToLower(candidate)); d <= limit {
+			if current, ok := best[code]; !ok || d < current {
+				best[code] = d
+			}
+		}
+	}
+	for _, c := range currencies {
+		consider(c.Code, c.Code)
+		consider(c.Name, c.Code)
+	}
+	for alias, code := range currencyAliases {
+		consider(alias, code)
+	}
+	for ticker := range cryptoAssets {
+		consider(ticker, ticker)
+	}
+
+	codes := make([]string, 0, len(best))
+	for code := range best {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if best[codes[i]] != best[codes[j]] {
+			return best[codes[i]] < best[codes[j]]
+		}
+		return codes[i] < codes[j]
+	})
+	if len(codes) > 3 {
+		codes = codes[:3]
+	}
+	return codes
+}
+
+// editDistance is the Levenshtein distance between a and b in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
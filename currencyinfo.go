@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// alternateCountryNames are second spellings in countryCurrencies, left out
+// when listing countries.
+var alternateCountryNames = map[string]bool{
+	"czechia":                  true,
+	"united states of america": true,
+}
+
+// countriesUsing returns the countries in the dataset whose local currency
+// is code, with their names capitalised.
+func countriesUsing(code string) []string {
+	var names []string
+	for name, currency := range countryCurrencies {
+		if currency == code && !alternateCountryNames[name] {
+			names = append(names, capitalizeWords(name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// capitalizeWords upper-cases the first letter of each word except short
+// joining words ("United States of America").
+func capitalizeWords(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		if i > 0 && (word == "of" || word == "and" || word == "the") {
+			continue
+		}
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// handleCurrencyInfo implements `nomad cv info <currency>`.
+func handleCurrencyInfo(args []string) {
+	if len(args) < 1 {
+		printError("Usage: nomad cv info <currency>\n")
+		printInfo("Example: nomad cv info idr\n")
+		os.Exit(1)
+	}
+	code := mustResolveCurrency(strings.Join(args, " "))
+	currency := findCurrency(code)
+	if currency == nil {
+		printError("Error: No details for %s in the currency dataset\n", code)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	printTitle("%s %s\n", iconCurrency(""), currency.Label())
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Symbol"), 14), currency.Symbol)
+
+	subunit := colorCyan("none in everyday use")
+	if currency.Subunit != "" {
+		subunit = fmt.Sprintf("%s (1/100)", currency.Subunit)
+	}
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Subunit"), 14), subunit)
+
+	if countries := countriesUsing(code); len(countries) > 0 {
+		fmt.Printf("  %s %s\n", padRight(iconLocation("Used in"), 14), strings.Join(countries, ", "))
+	}
+
+	if denominations, ok := banknotes[code]; ok {
+		notes := make([]string, len(denominations.Notes))
+		for i, note := range denominations.Notes {
+			notes[i] = formatFeeAmount(note)
+		}
+		fmt.Printf("  %s %s\n", padRight(iconCurrency("Notes"), 14), strings.Join(notes, " · "))
+		fmt.Printf("  %s multiples of %s\n", padRight(iconCurrency("ATMs"), 14), formatFeeAmount(denominations.ATMStep))
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// forecastHorizonDays is how far ahead Open-Meteo forecasts; later dates
+// get climate normals instead.
+const forecastHorizonDays = 15
+
+// normalsYears is how many past years the climate normals average.
+const normalsYears = 10
+
+// normalsWindowDays is how many days either side of the date count toward
+// the normals, to smooth out one-off weather.
+const normalsWindowDays = 3
+
+// dayForecastCacheTTL and climateCacheTTL are how long a date's forecast
+// and the years of history behind the normals are reused.
+const (
+	dayForecastCacheTTL = time.Hour
+	climateCacheTTL     = 30 * 24 * time.Hour
+)
+
+// DayOutlook is the weather expected on one date, either forecast or, too
+// far ahead for that, typical for the time of year.
+type DayOutlook struct {
+	Date      time.Time
+	Condition string
+	MaxC      float64
+	MinC      float64
+	PrecipMM  float64
+	// SunHours is the hours of sunshine, -1 when unknown
+	SunHours float64
+	// WindKmh and UVIndex are the day's highest, -1 when unknown
+	WindKmh float64
+	UVIndex float64
+	// RainChance is the chance of rain in percent, -1 when unknown; for
+	// normals it is the share of past days with rain
+	RainChance int
+	// Normals is set when the outlook averages past years rather than
+	// forecasting
+	Normals   bool
+	FromYear  int
+	ToYear    int
+	DaysCount int
+}
+
+// openMeteoDaily is the daily part of an Open-Meteo forecast or archive
+// response.
+type openMeteoDaily struct {
+	Daily struct {
+		Time        []string   `json:"time"`
+		WeatherCode []*int     `json:"weather_code"`
+		MaxC        []*float64 `json:"temperature_2m_max"`
+		MinC        []*float64 `json:"temperature_2m_min"`
+		PrecipMM    []*float64 `json:"precipitation_sum"`
+		RainChance  []*float64 `json:"precipitation_probability_max"`
+		SunSeconds  []*float64 `json:"sunshine_duration"`
+		WindKmh     []*float64 `json:"wind_speed_10m_max"`
+		UVIndex     []*float64 `json:"uv_index_max"`
+	} `json:"daily"`
+}
+
+// wmoConditions describes the WMO weather codes Open-Meteo reports.
+var wmoConditions = map[int]string{
+	0: "Clear", 1: "Mainly clear", 2: "Partly cloudy", 3: "Overcast",
+	45: "Fog", 48: "Freezing fog",
+	51: "Light drizzle", 53: "Drizzle", 55: "Heavy drizzle", 56: "Freezing drizzle", 57: "Freezing drizzle",
+	61: "Light rain", 63: "Rain", 65: "Heavy rain", 66: "Freezing rain", 67: "Freezing rain",
+	71: "Light snow", 73: "Snow", 75: "Heavy snow", 77: "Snow grains",
+	80: "Light showers", 81: "Showers", 82: "Heavy showers", 85: "Snow showers", 86: "Heavy snow showers",
+	95: "Thunderstorm", 96: "Thunderstorm with hail", 99: "Thunderstorm with hail",
+}
+
+// parseTravelDate reads the date given to --on: YYYY-MM-DD, "today",
+// "tomorrow" or "+N" days from today, which is midnight UTC.
+func parseTravelDate(text string, today time.Time) (time.Time, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	var date time.Time
+	switch {
+	case text == "today":
+		date = today
+	case text == "tomorrow":
+		date = today.AddDate(0, 0, 1)
+	case strings.HasPrefix(text, "+"):
+		days, err := strconv.Atoi(strings.TrimSuffix(text[1:], "d"))
+		if err != nil || days < 0 {
+			return time.Time{}, fmt.Errorf("invalid date '%s' (use YYYY-MM-DD, tomorrow or +N days)", text)
+		}
+		date = today.AddDate(0, 0, days)
+	default:
+		parsed, err := time.Parse("2006-01-02", text)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date '%s' (use YYYY-MM-DD, tomorrow or +N days)", text)
+		}
+		date = parsed
+	}
+
+	if date.Before(today) {
+		return time.Time{}, fmt.Errorf("%s is in the past", date.Format("2006-01-02"))
+	}
+	return date, nil
+}
+
+// getDayOutlook returns the forecast for date at a point, or the climate
+// normals when the date is beyond the forecast range.
+func getDayOutlook(lat, lon float64, date, today time.Time) (*DayOutlook, error) {
+	if date.Sub(today) <= forecastHorizonDays*24*time.Hour {
+		return getDayForecast(lat, lon, date)
+	}
+	return getClimateNormals(lat, lon, date, today)
+}
+
+func getDayForecast(lat, lon float64, date time.Time) (*DayOutlook, error) {
+	day := date.Format("2006-01-02")
+	var outlooks []DayOutlook
+	err := fetchCachedJSON(fmt.Sprintf("dayforecast:%.2f,%.2f:%s", lat, lon, day),
+		fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&daily=weather_code,temperature_2m_max,temperature_2m_min,precipitation_sum,precipitation_probability_max,wind_speed_10m_max,uv_index_max&timezone=auto&start_date=%s&end_date=%s",
+			lat, lon, day, day), dayForecastCacheTTL,
+		func(body []byte) error {
+			var response openMeteoDaily
+			if err := json.Unmarshal(body, &response); err != nil {
+				return schemaMismatch("api.open-meteo.com", "invalid JSON: "+err.Error(), body)
+			}
+			if outlooks = dailyOutlooks(&response); len(outlooks) == 0 {
+				return schemaMismatch("api.open-meteo.com", "no daily forecast", body)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	outlooks[0].Date = date
+	return &outlooks[0], nil
+}
+
+// dailyOutlooks reads the days of an Open-Meteo daily forecast, skipping
+// any without both temperatures.
+func dailyOutlooks(response *openMeteoDaily) []DayOutlook {
+	daily := response.Daily
+	// at reads day i of a series, which may be short or hold nulls
+	at := func(series []*float64, i int) (float64, bool) {
+		if i >= len(series) || series[i] == nil {
+			return 0, false
+		}
+		return *series[i], true
+	}
+
+	var outlooks []DayOutlook
+	for i, day := range daily.Time {
+		date, err := time.Parse("2006-01-02", day)
+		maxC, hasMax := at(daily.MaxC, i)
+		minC, hasMin := at(daily.MinC, i)
+		if err != nil || !hasMax || !hasMin {
+			continue
+		}
+		outlook := DayOutlook{Date: date, MaxC: maxC, MinC: minC, SunHours: -1, WindKmh: -1, UVIndex: -1, RainChance: -1}
+		if i < len(daily.WeatherCode) && daily.WeatherCode[i] != nil {
+			outlook.Condition = wmoConditions[*daily.WeatherCode[i]]
+		}
+		outlook.PrecipMM, _ = at(daily.PrecipMM, i)
+		if chance, ok := at(daily.RainChance, i); ok {
+			outlook.RainChance = int(chance)
+		}
+		if seconds, ok := at(daily.SunSeconds, i); ok {
+			outlook.SunHours = seconds / 3600
+		}
+		if wind, ok := at(daily.WindKmh, i); ok {
+			outlook.WindKmh = wind
+		}
+		if uv, ok := at(daily.UVIndex, i); ok {
+			outlook.UVIndex = uv
+		}
+		outlooks = append(outlooks, outlook)
+	}
+	return outlooks
+}
+
+// getClimateNormals averages the days around date's calendar day over the
+// last normalsYears years of Open-Meteo's historical archive.
+func getClimateNormals(lat, lon float64, date, today time.Time) (*DayOutlook, error) {
+	history, fromYear, toYear, err := getClimateHistory(lat, lon, today)
+	if err != nil {
+		return nil, err
+	}
+	daily := history.Daily
+
+	outlook := &DayOutlook{Date: date, Normals: true, FromYear: fromYear, ToYear: toYear,
+		SunHours: -1, WindKmh: -1, UVIndex: -1}
+	var rainyDays int
+	for i, day := range daily.Time {
+		past, err := time.Parse("2006-01-02", day)
+		if err != nil || daily.MaxC[i] == nil || daily.MinC[i] == nil || daily.PrecipMM[i] == nil {
+			continue
+		}
+		// The same calendar day in that year, so leap years line up
+		sameDay := time.Date(past.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		if math.Abs(past.Sub(sameDay).Hours()) > normalsWindowDays*24 {
+			continue
+		}
+		outlook.MaxC += *daily.MaxC[i]
+		outlook.MinC += *daily.MinC[i]
+		outlook.PrecipMM += *daily.PrecipMM[i]
+		if *daily.PrecipMM[i] >= 1 {
+			rainyDays++
+		}
+		outlook.DaysCount++
+	}
+	if outlook.DaysCount == 0 {
+		return nil, fmt.Errorf("no climate history around %s", date.Format("Jan 2"))
+	}
+
+	n := float64(outlook.DaysCount)
+	outlook.MaxC, outlook.MinC, outlook.PrecipMM = outlook.MaxC/n, outlook.MinC/n, outlook.PrecipMM/n
+	outlook.RainChance = int(math.Round(float64(rainyDays) / n * 100))
+	return outlook, nil
+}
+
+// handleWeatherOn implements `nomad weather <place> --on <date>`: the
+// forecast for a travel date, or what the weather is usually like then.
+func handleWeatherOn(query, dateText string) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	date, err := parseTravelDate(dateText, today)
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var weather *Weather
+	var outlook *DayOutlook
+	err = WithSpinner("Fetching forecast...", func() error {
+		var fetchErr error
+		if weather, fetchErr = currentWeather(query); fetchErr != nil {
+			return fetchErr
+		}
+		if !weather.HasCoords {
+			return fmt.Errorf("no coordinates for %s", weather.Location)
+		}
+		outlook, fetchErr = getDayOutlook(weather.Lat, weather.Lon, date, today)
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	if outlook.Normals {
+		announceResult("Around %s in %s, highs are usually %.0f and lows %.0f degrees", date.Format("January 2"),
+			weather.Location, tempValue(outlook.MaxC), tempValue(outlook.MinC))
+		printTitle("%s Typical weather in %s around %s\n", iconWeather(""), weather.Location, date.Format("Jan 2"))
+		fmt.Printf("  %s %s\n", padRight(iconTemp("Highs"), 14), colorYellow(formatTemp(outlook.MaxC)))
+		fmt.Printf("  %s %s\n", padRight(iconTemp("Lows"), 14), colorYellow(formatTemp(outlook.MinC)))
+		fmt.Printf("  %s %s %s\n", padRight(iconWeather("Rain"), 14), colorYellow(fmt.Sprintf("%d%% of days", outlook.RainChance)),
+			colorCyan(fmt.Sprintf("(%.1f mm a day on average)", outlook.PrecipMM)))
+		addSource(Source{What: "Climate normals", From: "Open-Meteo historical archive", Confidence: confidenceLow,
+			Updated: fmt.Sprintf("%d–%d", outlook.FromYear, outlook.ToYear),
+			Check:   fmt.Sprintf("an average of past years, not a forecast; check again within %d days of the date", forecastHorizonDays)})
+	} else {
+		announceResult("%s in %s on %s, %.0f to %.0f degrees", outlook.Condition, weather.Location, date.Format("Monday, January 2"),
+			tempValue(outlook.MinC), tempValue(outlook.MaxC))
+		printTitle("%s Forecast for %s on %s\n", iconWeather(""), weather.Location, date.Format("Mon, Jan 2"))
+		if outlook.Condition != "" {
+			fmt.Printf("  %s %s\n", padRight(iconWeather("Conditions"), 14), colorCyan(outlook.Condition))
+		}
+		fmt.Printf("  %s %s\n", padRight(iconTemp("High"), 14), colorYellow(formatTemp(outlook.MaxC)))
+		fmt.Printf("  %s %s\n", padRight(iconTemp("Low"), 14), colorYellow(formatTemp(outlook.MinC)))
+		rain := fmt.Sprintf("%.1f mm", outlook.PrecipMM)
+		if outlook.RainChance >= 0 {
+			rain = fmt.Sprintf("%d%% chance, %s", outlook.RainChance, rain)
+		}
+		fmt.Printf("  %s %s\n", padRight(iconWeather("Rain"), 14), colorYellow(rain))
+		if outlook.WindKmh >= 0 {
+			fmt.Printf("  %s %s\n", padRight(iconWeather("Wind"), 14), colorYellow("up to "+formatKmh(outlook.WindKmh)))
+		}
+		if outlook.UVIndex >= 0 {
+			fmt.Printf("  %s %s\n", padRight(iconWeather("UV index"), 14), colorYellow(fmt.Sprintf("%.0f", outlook.UVIndex)))
+		}
+		printActivityLine(outlook)
+		if date.Sub(today) > 7*24*time.Hour {
+			addSource(Source{What: "Forecast", From: "Open-Meteo", Confidence: confidenceMedium,
+				Check: "forecasts more than a week out change a lot, so check again nearer the date"})
+		}
+	}
+	printSources()
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// digestDays is the longer of the two windows in `nomad cv digest`; the
+// shorter one is the last week.
+const digestDays = 30
+
+// PairDigest is how one currency pair moved over the digest windows.
+type PairDigest struct {
+	From, To string
+	Points   []RatePoint
+	Err      error
+}
+
+// changeSince returns the percentage move from the last rate on or before
+// the given time to the latest rate.
+func (d PairDigest) changeSince(since time.Time) float64 {
+	start := d.Points[0]
+	for _, point := range d.Points {
+		if point.Date.After(since) {
+			break
+		}
+		start = point
+	}
+	last := d.Points[len(d.Points)-1]
+	return (last.Rate - start.Rate) / start.Rate * 100
+}
+
+// digestPairs returns the pairs named on the command line as FROM/TO, then
+// pinned_pairs from the config, then home_currency against the rate_table
+// shortlist.
+func digestPairs(args []string) [][2]string {
+	list := args
+	if len(list) == 0 {
+		list = strings.Split(config.Get("pinned_pairs"), ",")
+	}
+
+	var pairs [][2]string
+	for _, text := range list {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(text, "/")
+		if !ok {
+			printError("Error: Invalid pair '%s' (use FROM/TO, e.g. usd/thb)\n", text)
+			os.Exit(1)
+		}
+		pairs = append(pairs, [2]string{mustResolveCurrency(from), mustResolveCurrency(to)})
+	}
+	if len(pairs) > 0 {
+		return pairs
+	}
+
+	home := config.Get("home_currency")
+	if home == "" {
+		return nil
+	}
+	home = mustResolveCurrency(home)
+	for _, code := range strings.Split(config.Get("rate_table"), ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			if to := mustResolveCurrency(code); to != home {
+				pairs = append(pairs, [2]string{home, to})
+			}
+		}
+	}
+	return pairs
+}
+
+// handleDigest implements `nomad cv digest [FROM/TO...]`, summarising how
+// the user's pairs moved over the last 7 and 30 days.
+func handleDigest(args []string) {
+	pairs := digestPairs(args)
+	if len(pairs) == 0 {
+		printError("Usage: nomad cv digest [FROM/TO...]\n")
+		printInfo("Example: nomad cv digest usd/thb eur/gbp\n")
+		printInfo("Tip: set pinned_pairs in your config, or home_currency and rate_table, to skip the pairs\n")
+		os.Exit(1)
+	}
+
+	digests := make([]PairDigest, len(pairs))
+	WithSpinner("Fetching rate history...", func() error {
+		var wg sync.WaitGroup
+		for i, pair := range pairs {
+			wg.Add(1)
+			go func(i int, from, to string) {
+				defer wg.Done()
+				digests[i] = PairDigest{From: from, To: to}
+				digests[i].Points, digests[i].Err = getRateHistory(from, to, digestDays)
+				if digests[i].Err == nil && len(digests[i].Points) < 2 {
+					digests[i].Err = fmt.Errorf("not enough rate history")
+				}
+			}(i, pair[0], pair[1])
+		}
+		wg.Wait()
+		return nil
+	})
+
+	now := time.Now()
+	weekAgo := now.AddDate(0, 0, -7)
+
+	fmt.Println()
+	printTitle("%s FX digest, week to %s\n", iconCurrency(""), now.Format("Mon, Jan 2"))
+
+	table := NewTable("Pair", "Latest", "7 days", fmt.Sprintf("%d days", digestDays), fmt.Sprintf("%d-day range", digestDays), "Trend")
+	var mover *PairDigest
+	var moverChange float64
+	for i, digest := range digests {
+		pair := digest.From + "/" + digest.To
+		if digest.Err != nil {
+			logVerbose("%s history failed: %v", pair, digest.Err)
+			table.AddRow(pair, colorRed("no history"), "", "", "", "")
+			continue
+		}
+
+		values := make([]float64, len(digest.Points))
+		low, high := math.Inf(1), math.Inf(-1)
+		for j, point := range digest.Points {
+			values[j] = point.Rate
+			low, high = math.Min(low, point.Rate), math.Max(high, point.Rate)
+		}
+		week, month := digest.changeSince(weekAgo), digest.changeSince(now.AddDate(0, 0, -digestDays))
+		if mover == nil || math.Abs(week) > math.Abs(moverChange) {
+			mover, moverChange = &digests[i], week
+		}
+
+		table.AddRow(pair, colorYellow(formatGrouped(values[len(values)-1])),
+			colorForChange(week)(fmt.Sprintf("%+.2f%%", week)), colorForChange(month)(fmt.Sprintf("%+.2f%%", month)),
+			formatGrouped(low)+" – "+formatGrouped(high), colorCyan(sparkline(values)))
+	}
+	table.Print()
+
+	if mover != nil {
+		fmt.Println()
+		fmt.Printf("  %s %s %s\n", padRight(iconSuccess("Biggest move"), 14), mover.From+"/"+mover.To,
+			colorForChange(moverChange)(fmt.Sprintf("%+.2f%% this week", moverChange)))
+	}
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-ping/ping"
+	"golang.org/x/term"
+)
+
+// doctorCheck is the outcome of one environment check.
+type doctorCheck struct {
+	Name   string
+	Status string // pass, warn or fail
+	Detail string
+	Fix    string
+}
+
+// configKeys lists every setting nomad reads from the config file.
+var configKeys = []string{
+	"ip_family", "http3", "verbose", "doh", "doh_provider", "plain", "speak",
+	"qr_invert", "map_provider", "home_currency", "pinned_pairs", "cache",
+	"cache_redis", "rate_provider", "openexchangerates_key", "fixer_key",
+	"default_from", "default_to", "rate_table", "cross_via", "basket", "ticker", "chronotype", "home_city",
+	"sources", "units", "weather_provider", "openweathermap_key",
+	"comfort_band",
+}
+
+// handleDoctor checks that the environment can run every command and
+// suggests fixes for anything that cannot.
+func handleDoctor() {
+	checks := []doctorCheck{
+		checkConfig(),
+		checkTimezoneData(),
+		checkDataDir(),
+		checkICMP(),
+		checkTerminal(),
+		checkDiagnostics(),
+	}
+
+	var providerChecks []doctorCheck
+	WithSpinner("Contacting providers...", func() error {
+		providerChecks = checkProviders()
+		return nil
+	})
+	checks = append(checks, providerChecks...)
+
+	fmt.Println()
+	printTitle("%s Nomad Doctor\n", iconQuality(""))
+
+	table := NewTable("Check", "Result", "Details").SetMaxWidth(2, 60)
+	failed := 0
+	for _, check := range checks {
+		status := colorGreen("pass")
+		switch check.Status {
+		case "warn":
+			status = colorYellow("warn")
+		case "fail":
+			status = colorRed("fail")
+			failed++
+		}
+
+		details := check.Detail
+		if check.Fix != "" {
+			details += " → " + check.Fix
+		}
+		table.AddRow(check.Name, status, details)
+	}
+	table.Print()
+
+	fmt.Println()
+	if failed > 0 {
+		printError("%d check(s) failed\n", failed)
+		os.Exit(1)
+	}
+	printSuccess("Everything looks good\n")
+}
+
+func checkConfig() doctorCheck {
+	check := doctorCheck{Name: "Config"}
+
+	path, err := configPath()
+	if err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		return check
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		check.Fix = "fix the line or remove " + path
+		return check
+	}
+	if len(cfg.values) == 0 {
+		check.Status, check.Detail = "pass", "no config file, using defaults ("+path+")"
+		return check
+	}
+
+	var problems []string
+	for key := range cfg.values {
+		if !containsString(configKeys, key) {
+			problems = append(problems, fmt.Sprintf("unknown key '%s'", key))
+		}
+	}
+	if family := cfg.Get("ip_family"); family != "" && family != "4" && family != "6" {
+		problems = append(problems, "ip_family must be 4 or 6")
+	}
+	if cfg.Get("doh_provider") != "" {
+		if _, err := resolveDoHProvider(cfg.Get("doh_provider")); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if provider := strings.ToLower(cfg.Get("map_provider")); provider != "" &&
+		!containsString([]string{"osm", "openstreetmap", "google", "apple"}, provider) {
+		problems = append(problems, "map_provider must be osm, google or apple")
+	}
+	if backend := strings.ToLower(cfg.Get("cache")); backend != "" &&
+		!containsString([]string{"memory", "disk", "redis"}, backend) {
+		problems = append(problems, "cache must be memory, disk or redis")
+	}
+	if provider := strings.ToLower(cfg.Get("rate_provider")); provider != "" && rateProviders[provider] == nil {
+		problems = append(problems, "rate_provider must be "+strings.Join(rateProviderNames(), ", "))
+	}
+	if band := cfg.Get("comfort_band"); band != "" {
+		if _, _, err := parseComfortBand(band); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	for _, key := range []string{"home_currency", "default_from", "default_to"} {
+		if code := cfg.Get(key); code != "" && findCurrency(strings.ToUpper(code)) == nil && !isCrypto(code) {
+			problems = append(problems, fmt.Sprintf("unknown %s '%s'", key, code))
+		}
+	}
+	sort.Strings(problems)
+
+	if len(problems) > 0 {
+		check.Status, check.Detail = "warn", strings.Join(problems, "; ")
+		check.Fix = "edit " + path
+		return check
+	}
+	check.Status, check.Detail = "pass", path
+	return check
+}
+
+func checkTimezoneData() doctorCheck {
+	check := doctorCheck{Name: "Timezone data"}
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		check.Fix = "install the tzdata package or set ZONEINFO"
+		return check
+	}
+	check.Status, check.Detail = "pass", "time zones load"
+	return check
+}
+
+func checkDataDir() doctorCheck {
+	check := doctorCheck{Name: "Data directory"}
+
+	path, err := dataPath(".doctor")
+	if err == nil {
+		err = os.WriteFile(path, []byte("ok"), 0o644)
+		os.Remove(path)
+	}
+	if err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		check.Fix = "make the config directory writable or set NOMAD_CONFIG"
+		return check
+	}
+	check.Status, check.Detail = "pass", "writable"
+	return check
+}
+
+// diagnosticsWindow is how far back checkDiagnostics looks for unexpected
+// provider responses.
+const diagnosticsWindow = 7 * 24 * time.Hour
+
+// checkDiagnostics reports providers that recently answered in a format
+// nomad didn't understand, which usually means an API changed or retired.
+func checkDiagnostics() doctorCheck {
+	check := doctorCheck{Name: "Provider responses"}
+
+	entries, err := recentDiagnostics(time.Now().Add(-diagnosticsWindow))
+	if err != nil {
+		check.Status, check.Detail = "warn", err.Error()
+		return check
+	}
+	if len(entries) == 0 {
+		check.Status, check.Detail = "pass", "no unexpected responses in the last 7 days"
+		return check
+	}
+
+	counts := make(map[string]int)
+	var providers []string
+	for _, entry := range entries {
+		if counts[entry.Provider] == 0 {
+			providers = append(providers, entry.Provider)
+		}
+		counts[entry.Provider]++
+	}
+	var parts []string
+	for _, provider := range providers {
+		parts = append(parts, fmt.Sprintf("%s ×%d", provider, counts[provider]))
+	}
+	last := entries[len(entries)-1]
+	check.Status = "warn"
+	check.Detail = fmt.Sprintf("unexpected responses from %s; last: %s (%s)", strings.Join(parts, ", "), last.Problem, last.Time.Format("Jan 2 15:04"))
+	path, _ := dataPath(diagnosticsFile)
+	check.Fix = "the API may have changed; update nomad or attach " + path + " to a bug report"
+	return check
+}
+
+// checkICMP sends one unprivileged ping to localhost, the same way the ping
+// command does.
+func checkICMP() doctorCheck {
+	check := doctorCheck{Name: "ICMP ping"}
+
+	pinger, err := ping.NewPinger("127.0.0.1")
+	if err == nil {
+		pinger.Count = 1
+		pinger.Timeout = time.Second
+		pinger.SetPrivileged(false)
+		err = pinger.Run()
+	}
+	if err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		if runtime.GOOS == "linux" {
+			check.Fix = `sudo sysctl -w net.ipv4.ping_group_range="0 2147483647"`
+		} else {
+			check.Fix = "allow unprivileged ICMP or run ping as administrator"
+		}
+		return check
+	}
+	if pinger.Statistics().PacketsRecv == 0 {
+		check.Status, check.Detail = "warn", "no reply from localhost"
+		check.Fix = "check firewall rules for ICMP"
+		return check
+	}
+	check.Status, check.Detail = "pass", "unprivileged ping works"
+	return check
+}
+
+func checkTerminal() doctorCheck {
+	check := doctorCheck{Name: "Terminal"}
+
+	if !isInteractive() {
+		check.Status, check.Detail = "warn", "not a terminal, pickers are disabled"
+		return check
+	}
+
+	var details []string
+	if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		details = append(details, fmt.Sprintf("%dx%d", width, height))
+	}
+	if ansiEnabled {
+		details = append(details, "colors")
+	} else {
+		details = append(details, "no colors")
+	}
+	if termName := os.Getenv("TERM"); termName != "" {
+		details = append(details, "TERM="+termName)
+	}
+	check.Detail = strings.Join(details, ", ")
+
+	if !ansiEnabled && !options.Plain {
+		check.Status = "warn"
+		check.Fix = "use a terminal with ANSI color support"
+		return check
+	}
+	check.Status = "pass"
+	return check
+}
+
+// checkProviders makes a request to every API host used by the commands.
+// Any HTTP response counts as reachable.
+func checkProviders() []doctorCheck {
+	// Hosts of subcommands, which commandHosts does not cover
+	seen := map[string]bool{"www.speedtest.net": true, "api.frankfurter.app": true,
+		"api.wise.com": true, "www.visa.co.uk": true, "www.mastercard.us": true,
+		"api.worldbank.org": true, "api.open-meteo.com": true,
+		"api.openweathermap.org": true, "archive-api.open-meteo.com": true}
+	for _, hosts := range commandHosts {
+		for _, host := range hosts {
+			seen[host] = true
+		}
+	}
+	hosts := make([]string, 0, len(seen))
+	for host := range seen {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	client := newHTTPClient(5 * time.Second)
+	checks := make([]doctorCheck, len(hosts))
+
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			checks[i] = checkProvider(client, host)
+		}(i, host)
+	}
+	wg.Wait()
+
+	return checks
+}
+
+func checkProvider(client *http.Client, host string) doctorCheck {
+	check := doctorCheck{Name: host}
+
+	status, latency, err := probeHost(client, host)
+	if err != nil {
+		check.Status, check.Detail = "fail", err.Error()
+		check.Fix = "check your connection, or try --doh if DNS is blocked"
+		return check
+	}
+
+	check.Status = "pass"
+	check.Detail = fmt.Sprintf("HTTP %d in %s", status, latency)
+	return check
+}
+
+// probeHost sends a HEAD request to host, returning the status code and
+// how long the response took.
+func probeHost(client *http.Client, host string) (int, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.Head("https://" + host + "/")
+	if err != nil {
+		return 0, 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, time.Since(start).Round(time.Millisecond), nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dohProvider describes a DNS-over-HTTPS endpoint speaking the JSON API
+// supported by Cloudflare and Google.
+type dohProvider struct {
+	URL string
+	// Bootstrap is the IP address used to reach the endpoint, so that the
+	// resolver does not depend on the system DNS it is replacing
+	Bootstrap string
+}
+
+var dohProviders = map[string]dohProvider{
+	"cloudflare": {URL: "https://cloudflare-dns.com/dns-query", Bootstrap: "1.1.1.1"},
+	"google":     {URL: "https://dns.google/resolve", Bootstrap: "8.8.8.8"},
+}
+
+// dohResponse is the subset of the DNS JSON API response we use.
+type dohResponse struct {
+	Status int `json:"Status"`
+	Answer []struct {
+		Type int    `json:"type"`
+		Data string `json:"data"`
+	} `json:"Answer"`
+}
+
+const (
+	dnsTypeA    = 1
+	dnsTypeAAAA = 28
+)
+
+var (
+	dohClientOnce sync.Once
+	dohClient     *http.Client
+)
+
+// resolveDoHProvider turns the configured provider name or URL into a
+// provider definition.
+func resolveDoHProvider(name string) (dohProvider, error) {
+	if name == "" {
+		name = "cloudflare"
+	}
+	if provider, ok := dohProviders[strings.ToLower(name)]; ok {
+		return provider, nil
+	}
+
+	u, err := url.Parse(name)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return dohProvider{}, fmt.Errorf("unknown DoH provider '%s' (use cloudflare, google or an https:// URL)", name)
+	}
+	return dohProvider{URL: name}, nil
+}
+
+// getDoHClient returns the HTTP client used to talk to the DoH endpoint.
+// It dials the bootstrap address directly instead of going through the
+// shared transport, which would resolve hostnames via DoH again.
+func getDoHClient(provider dohProvider) *http.Client {
+	dohClientOnce.Do(func() {
+		transport := &http.Transport{
+			ForceAttemptHTTP2:   true,
+			TLSHandshakeTimeout: 5 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+		}
+		// Custom providers without a bootstrap address are resolved by the
+		// system resolver
+		if provider.Bootstrap != "" {
+			transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				_, port, err := net.SplitHostPort(addr)
+				if err != nil {
+					return nil, err
+				}
+				return dialer.DialContext(ctx, network, net.JoinHostPort(provider.Bootstrap, port))
+			}
+		}
+		dohClient = &http.Client{Timeout: 5 * time.Second, Transport: transport}
+	})
+	return dohClient
+}
+
+// lookupHostDoH resolves host over DNS-over-HTTPS, querying A and AAAA
+// records concurrently.
+func lookupHostDoH(ctx context.Context, provider dohProvider, host string) ([]string, error) {
+	types := []int{dnsTypeA, dnsTypeAAAA}
+	switch options.IPFamily {
+	case "4":
+		types = []int{dnsTypeA}
+	case "6":
+		types = []int{dnsTypeAAAA}
+	}
+
+	results := make([][]string, len(types))
+	errs := make([]error, len(types))
+	var wg sync.WaitGroup
+	for i, qtype := range types {
+		wg.Add(1)
+		go func(i, qtype int) {
+			defer wg.Done()
+			results[i], errs[i] = queryDoH(ctx, provider, host, qtype)
+		}(i, qtype)
+	}
+	wg.Wait()
+
+	var addrs []string
+	for i := range types {
+		addrs = append(addrs, results[i]...)
+	}
+	if len(addrs) == 0 {
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, fmt.Errorf("no DNS records found for %s", host)
+	}
+
+	logVerbose("resolved %s via DoH (%s): %s", host, provider.URL, strings.Join(addrs, ", "))
+	return addrs, nil
+}
+
+// queryDoH performs a single DNS-over-HTTPS query for one record type.
+func queryDoH(ctx context.Context, provider dohProvider, host string, qtype int) ([]string, error) {
+	params := url.Values{}
+	params.Add("name", host)
+	params.Add("type", fmt.Sprint(qtype))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", provider.URL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DoH request: %v", err)
+	}
+	req.Header.Set("Accept", "application/dns-json")
+
+	resp, err := getDoHClient(provider).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("DoH query failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server returned status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DoH response: %v", err)
+	}
+
+	var response dohResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse DoH response: %v", err)
+	}
+
+	// Status is the DNS RCODE; 3 (NXDOMAIN) means the name does not exist
+	if response.Status != 0 {
+		return nil, fmt.Errorf("DoH lookup for %s failed with DNS status %d", host, response.Status)
+	}
+
+	// Answers may include CNAME records; keep only the addresses
+	var addrs []string
+	for _, answer := range response.Answer {
+		if answer.Type == qtype {
+			addrs = append(addrs, answer.Data)
+		}
+	}
+	return addrs, nil
+}
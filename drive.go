@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// handleDrive shows the road rules of a country and whether a visitor's
+// licence is enough, e.g. `nomad drive thailand --license de`.
+func handleDrive(args []string) {
+	args, license, _ := popFlagValue(args, "--license")
+
+	if len(args) < 1 {
+		printError("Usage: nomad drive <country> [--license COUNTRY_CODE]\n")
+		printInfo("Example: nomad drive thailand --license de\n")
+		os.Exit(1)
+	}
+
+	country := findCountry(strings.Join(args, " "))
+	if country == nil {
+		printError("Error: No driving rules for '%s'\n", strings.Join(args, " "))
+		printInfo("Known countries: %s\n", strings.Join(countryNames(), ", "))
+		os.Exit(1)
+	}
+	rules := country.Drive
+
+	idp := rules.IDP
+	if license != "" {
+		code := strings.ToUpper(license)
+		if from := findCountry(license); from != nil {
+			code = from.Code
+		}
+		switch {
+		case code == country.Code:
+			idp = "Not needed, your licence is from here"
+		case containsString(euCountries, code) && containsString(euCountries, country.Code):
+			idp = "Not needed, EU and EEA licences are valid here"
+		}
+	}
+
+	fmt.Println()
+	printTitle("%s Driving in %s\n", iconLocation(""), country.Name)
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Drive on"), 16), colorYellow("the "+rules.Side))
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Permit"), 16), idp)
+
+	motorway := formatKmh(float64(rules.Motorway))
+	if rules.Motorway == 0 {
+		motorway = "none"
+	}
+	fmt.Printf("  %s %s %s\n", padRight(iconSpeed("Speed limits"), 16),
+		colorYellow(fmt.Sprintf("%s / %s / %s", formatKmh(float64(rules.Urban)), formatKmh(float64(rules.Rural)), motorway)),
+		colorCyan("(town / rural / motorway)"))
+
+	bac := colorYellow(fmt.Sprintf("%.2f%%", rules.BAC))
+	if rules.BAC == 0 {
+		bac = colorRed("zero tolerance")
+	}
+	fmt.Printf("  %s %s\n", padRight(iconError("Alcohol limit"), 16), bac)
+
+	if rules.Notes != "" {
+		fmt.Printf("  %s %s\n", padRight(iconInfo("Good to know"), 16), rules.Notes)
+	}
+	addSource(Source{What: "Driving rules", From: builtInData, Confidence: confidenceMedium,
+		Check: "general rules for visitors, so check with your rental company and local authorities"})
+	printSources()
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// emitEvent writes one NDJSON event to stdout for --ndjson, so samples
+// from long-running commands can be piped into jq or a dashboard as they
+// arrive. Every event carries its type and an RFC 3339 timestamp.
+func emitEvent(event string, fields map[string]interface{}) {
+	line := map[string]interface{}{
+		"event": event,
+		"time":  time.Now().Format(time.RFC3339),
+	}
+	for key, value := range fields {
+		line[key] = value
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode %s event: %v\n", event, err)
+		return
+	}
+	os.Stdout.Write(append(data, '\n'))
+}
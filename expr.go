@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// evalExpression evaluates simple arithmetic such as "1200+450*3" or
+// "(35+12.5)/2", so a bill can be summed and converted in one go. It
+// supports + - * /, parentheses and unary minus with the usual precedence,
+// and numbers with exponents such as 2.5e6.
+func evalExpression(s string) (float64, error) {
+	p := &exprParser{input: strings.ReplaceAll(s, " ", "")}
+	if p.input == "" {
+		return 0, fmt.Errorf("empty expression")
+	}
+
+	value, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected '%c' in '%s'", p.input[p.pos], s)
+	}
+	return value, nil
+}
+
+// exprParser is a recursive descent parser over an expression without
+// spaces.
+type exprParser struct {
+	input string
+	pos   int
+}
+
+// peek returns the next byte, or 0 at the end of the input.
+func (p *exprParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *exprParser) parseSum() (float64, error) {
+	value, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for op := p.peek(); op == '+' || op == '-'; op = p.peek() {
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			value += right
+		} else {
+			value -= right
+		}
+	}
+	return value, nil
+}
+
+func (p *exprParser) parseProduct() (float64, error) {
+	value, err := p.parseFactor()
+	if err != nil {
+		return 0, err
+	}
+	for op := p.peek(); op == '*' || op == 'x' || op == '/'; op = p.peek() {
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return 0, err
+		}
+		if op == '/' {
+			if right == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			value /= right
+		} else {
+			value *= right
+		}
+	}
+	return value, nil
+}
+
+func (p *exprParser) parseFactor() (float64, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		value, err := p.parseFactor()
+		return -value, err
+	case '+':
+		p.pos++
+		return p.parseFactor()
+	case '(':
+		p.pos++
+		value, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return value, nil
+	}
+
+	start := p.pos
+	for c := p.peek(); (c >= '0' && c <= '9') || c == '.'; c = p.peek() {
+		p.pos++
+	}
+	// An exponent, as in 1e3 or 2.5E-2, needs digits after the sign
+	if c := p.peek(); start < p.pos && (c == 'e' || c == 'E') {
+		end := p.pos + 1
+		if end < len(p.input) && (p.input[end] == '+' || p.input[end] == '-') {
+			end++
+		}
+		if end < len(p.input) && p.input[end] >= '0' && p.input[end] <= '9' {
+			for p.pos = end; p.peek() >= '0' && p.peek() <= '9'; {
+				p.pos++
+			}
+		}
+	}
+	if start == p.pos {
+		if p.pos >= len(p.input) {
+			return 0, fmt.Errorf("expression ends too early")
+		}
+		return 0, fmt.Errorf("unexpected '%c'", p.input[p.pos])
+	}
+	return strconv.ParseFloat(p.input[start:p.pos], 64)
+}
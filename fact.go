@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// factTTL is how long a fetched fact is served from the cache.
+var factTTL = map[string]time.Duration{
+	"weather": 15 * time.Minute,
+	"rate":    time.Hour,
+}
+
+// factCache returns where facts are remembered between runs: the
+// configured cache, or the disk when that only lasts one run. Private facts,
+// like the weather where the caller is, never go to a shared Redis.
+func factCache(private bool) Cache {
+	if backend := strings.ToLower(config.Get("cache")); backend != "" && backend != "memory" &&
+		!(private && backend == "redis") {
+		return sharedCache()
+	}
+	cache, err := newDiskCache("cache")
+	if err != nil {
+		return newMemoryCache()
+	}
+	return cache
+}
+
+// weatherFactFields maps fact names to wttr.in current conditions. wttr.in
+// sends imperial values alongside the metric ones, which are used with
+// --units imperial.
+var weatherFactFields = map[string]func(current *WttrCurrent, imperial bool) string{
+	"temp": func(c *WttrCurrent, imperial bool) string {
+		if imperial {
+			return string(c.TempF)
+		}
+		return string(c.TempC)
+	},
+	"feels": func(c *WttrCurrent, imperial bool) string {
+		if imperial {
+			return string(c.FeelsLikeF)
+		}
+		return string(c.FeelsLikeC)
+	},
+	"humidity": func(c *WttrCurrent, _ bool) string { return string(c.Humidity) },
+	"uv":       func(c *WttrCurrent, _ bool) string { return string(c.UVIndex) },
+	"wind": func(c *WttrCurrent, imperial bool) string {
+		if imperial {
+			return string(c.WindMiles)
+		}
+		return string(c.WindKmph)
+	},
+	"condition": func(c *WttrCurrent, _ bool) string { return c.WeatherDesc.String() },
+}
+
+// handleFact prints a single undecorated value for phone automation and
+// voice assistants, e.g. `nomad fact rate.usd.thb`.
+func handleFact(args []string) {
+	if len(args) < 1 {
+		printError("Usage: nomad fact <weather.FIELD[.CITY]|rate.FROM.TO|time.CITY>\n")
+		printInfo("Example: nomad fact weather.temp\n")
+		printInfo("Example: nomad fact rate.usd.thb\n")
+		printInfo("Example: nomad fact time.tokyo\n")
+		os.Exit(1)
+	}
+
+	value, err := lookupFact(strings.ToLower(args[0]))
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(value)
+}
+
+// lookupFact resolves a dotted fact path, serving recent values from the
+// cache so repeated calls stay fast.
+func lookupFact(path string) (string, error) {
+	parts := strings.Split(path, ".")
+	kind := parts[0]
+
+	// Time is computed locally for known cities and never cached
+	if kind == "time" {
+		return timeFact(parts[1:])
+	}
+
+	ttl, ok := factTTL[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown fact '%s' (use weather, rate or time)", kind)
+	}
+
+	key := "fact:" + path
+	if imperial() {
+		key += ":" + unitsImperial
+	}
+	// Weather without a city is for the caller's IP-based location
+	private := kind == "weather" && len(parts) == 2
+	value, err := cachedFetchIn(factCache(private), key, ttl, func() ([]byte, error) {
+		var value string
+		var err error
+		switch kind {
+		case "weather":
+			value, err = weatherFact(parts[1:])
+		case "rate":
+			value, err = rateFact(parts[1:])
+		}
+		return []byte(value), err
+	})
+	return string(value), err
+}
+
+// weatherFact returns a current condition, e.g. weather.temp or
+// weather.humidity.lisbon. Without a city the IP-based location is used.
+func weatherFact(parts []string) (string, error) {
+	if len(parts) < 1 {
+		return "", fmt.Errorf("missing weather field (use temp, feels, humidity, uv, wind or condition)")
+	}
+
+	field, ok := weatherFactFields[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("unknown weather field '%s' (use temp, feels, humidity, uv, wind or condition)", parts[0])
+	}
+
+	response, err := wttr.Forecast(strings.Join(parts[1:], " "))
+	if err != nil {
+		return "", err
+	}
+
+	// Forecast checked there is a current observation
+	current, _ := response.Current()
+	value := strings.TrimSpace(field(current, imperial()))
+	if value == "" {
+		return "", fmt.Errorf("no %s returned", parts[0])
+	}
+	return value, nil
+}
+
+// rateFact returns the exchange rate for rate.FROM.TO.
+func rateFact(parts []string) (string, error) {
+	if len(parts) != 2 || len(parts[0]) != 3 || len(parts[1]) != 3 {
+		return "", fmt.Errorf("rate facts look like rate.usd.thb")
+	}
+
+	rate, err := getExchangeRate(strings.ToUpper(parts[0]), strings.ToUpper(parts[1]))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(rate, 'f', 4, 64), nil
+}
+
+// timeFact returns the local time in a city, e.g. time.tokyo or
+// time.new-york. Cities outside the built-in list are geocoded.
+func timeFact(parts []string) (string, error) {
+	if len(parts) < 1 {
+		return "", fmt.Errorf("missing city, e.g. time.tokyo")
+	}
+	name := strings.Join(parts, " ")
+
+	timezone := ""
+	if city := findCity(name); city != nil {
+		timezone = city.Timezone
+	} else {
+		location, err := getLocationInfo(name)
+		if err != nil {
+			return "", err
+		}
+		timezone = location.Timezone
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return "", fmt.Errorf("error loading timezone: %v", err)
+	}
+	return time.Now().In(loc).Format("15:04"), nil
+}
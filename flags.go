@@ -0,0 +1,53 @@
+package main
+
+import "strings"
+
+// popFlag removes every occurrence of a boolean flag from args and reports
+// whether it was present. Flags may appear anywhere among the positional
+// arguments, e.g. "nomad w Lisbon --pick".
+func popFlag(args []string, names ...string) ([]string, bool) {
+	rest := make([]string, 0, len(args))
+	found := false
+	for _, arg := range args {
+		if matchesFlag(arg, names) {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// popFlagValue removes a flag that takes a value, accepting both
+// "--name value" and "--name=value". The last occurrence wins.
+func popFlagValue(args []string, names ...string) ([]string, string, bool) {
+	rest := make([]string, 0, len(args))
+	var value string
+	found := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if name, v, ok := strings.Cut(arg, "="); ok && matchesFlag(name, names) {
+			value, found = v, true
+			continue
+		}
+		if matchesFlag(arg, names) {
+			found = true
+			if i+1 < len(args) {
+				value = args[i+1]
+				i++
+			}
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, value, found
+}
+
+func matchesFlag(arg string, names []string) bool {
+	for _, name := range names {
+		if arg == name {
+			return true
+		}
+	}
+	return false
+}
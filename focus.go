@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// chronotypePeaks is the hour of day each chronotype tends to focus best.
+var chronotypePeaks = map[string]float64{
+	"lark":         9.5,
+	"intermediate": 11.5,
+	"owl":          16.5,
+}
+
+// chronotypeAliases maps everyday descriptions to a chronotype.
+var chronotypeAliases = map[string]string{
+	"morning": "lark",
+	"early":   "lark",
+	"evening": "owl",
+	"night":   "owl",
+	"late":    "owl",
+	"neither": "intermediate",
+	"middle":  "intermediate",
+}
+
+// Focus hours are scored from the first to the last hour of the strip.
+const (
+	focusFirstHour = 6
+	focusLastHour  = 23
+)
+
+// FocusConditions is what the forecast says about one hour of the day.
+type FocusConditions struct {
+	Hour      int
+	FeelsLike float64
+	Daylight  bool
+}
+
+// focusScore rates an hour from 0 to 1 for deep work: mostly how close it
+// is to the chronotype's peak, then daylight and a comfortable temperature.
+func focusScore(c FocusConditions, peak float64) float64 {
+	chrono := math.Exp(-math.Pow(float64(c.Hour)+0.5-peak, 2) / (2 * 2.5 * 2.5))
+
+	daylight := 0.3
+	if c.Daylight {
+		daylight = 1
+	}
+
+	// 18-24°C feels right indoors or out; comfort fades over 10 degrees
+	comfort := 1.0
+	switch {
+	case c.FeelsLike < 18:
+		comfort = math.Max(0, 1-(18-c.FeelsLike)/10)
+	case c.FeelsLike > 24:
+		comfort = math.Max(0, 1-(c.FeelsLike-24)/10)
+	}
+
+	return 0.6*chrono + 0.2*daylight + 0.2*comfort
+}
+
+// handleFocus implements `nomad focus [city] [--chronotype lark|owl]
+// [--hours N]`, suggesting today's best deep-work window from the forecast
+// and the user's chronotype.
+func handleFocus(args []string) {
+	args, chronotype, _ := popFlagValue(args, "--chronotype")
+	args, hoursStr, _ := popFlagValue(args, "--hours")
+	query, _ := resolveLocation(args)
+
+	if chronotype == "" {
+		chronotype = config.Get("chronotype")
+	}
+	chronotype = strings.ToLower(strings.TrimSpace(chronotype))
+	if alias, ok := chronotypeAliases[chronotype]; ok {
+		chronotype = alias
+	}
+	if chronotype == "" {
+		chronotype = "intermediate"
+	}
+	peak, ok := chronotypePeaks[chronotype]
+	if !ok {
+		printError("Error: Unknown chronotype '%s' (use lark, intermediate or owl)\n", chronotype)
+		os.Exit(1)
+	}
+
+	window := 2
+	if hoursStr != "" {
+		var err error
+		if window, err = strconv.Atoi(hoursStr); err != nil || window < 1 || window > 6 {
+			printError("Error: Invalid number of hours '%s' (use 1 to 6)\n", hoursStr)
+			os.Exit(1)
+		}
+	}
+
+	var response *WttrResponse
+	err := WithSpinner("Fetching weather data...", func() error {
+		var fetchErr error
+		response, fetchErr = wttr.Forecast(query)
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	today, ok := response.Today()
+	if !ok || len(today.Hourly) == 0 {
+		printError("Error: No hourly forecast for today\n")
+		os.Exit(1)
+	}
+	sunrise, sunset := today.Sun()
+	conditions := focusConditions(today.Hourly, sunrise, sunset)
+
+	// Hours already gone at the location don't count
+	now := focusFirstHour
+	if current, ok := response.Current(); ok {
+		if observed, err := current.LocalTime(); err == nil {
+			now = max(now, observed.Hour())
+		}
+	}
+	if now+window > focusLastHour+1 {
+		printInfo("Today is nearly over; try again tomorrow morning\n")
+		return
+	}
+
+	scores := make([]float64, len(conditions))
+	for i, c := range conditions {
+		scores[i] = focusScore(c, peak)
+	}
+	best := bestFocusWindow(scores, focusFirstHour, window)
+	remaining := bestFocusWindow(scores, now, window)
+
+	location := query
+	if area, ok := response.Area(); ok {
+		if name := area.AreaName.String(); name != "" {
+			location = name
+		}
+	}
+
+	fmt.Println()
+	if location != "" {
+		printTitle("%s Deep work in %s today\n", iconTime(""), location)
+	} else {
+		printTitle("%s Deep work today\n", iconTime(""))
+	}
+
+	var labels, strip strings.Builder
+	for i, c := range conditions {
+		if c.Hour%2 == 0 {
+			fmt.Fprintf(&labels, "%02d", c.Hour)
+		} else {
+			labels.WriteString("  ")
+		}
+
+		level := int(scores[i] * float64(len(sparkBlocks)-1))
+		bar := strings.Repeat(string(sparkBlocks[level]), 2)
+		switch {
+		case c.Hour >= remaining && c.Hour < remaining+window:
+			bar = colorGreen(bar)
+		case c.Hour < now:
+			bar = colorBlue(bar)
+		default:
+			bar = colorCyan(bar)
+		}
+		strip.WriteString(bar)
+	}
+	fmt.Printf("  %s\n  %s\n\n", strip.String(), labels.String())
+
+	describe := func(start int) string {
+		middle := conditions[start-focusFirstHour+window/2]
+		reasons := []string{chronotype, "feels like " + formatTemp(middle.FeelsLike)}
+		if middle.Daylight {
+			reasons = append(reasons, "daylight")
+		}
+		return colorYellow(fmt.Sprintf("%02d:00–%02d:00", start, start+window)) + " " + colorCyan("("+strings.Join(reasons, ", ")+")")
+	}
+	if best < now {
+		fmt.Printf("  %s %s, already passed\n", padRight(iconInfo("Best window"), 14), describe(best))
+		fmt.Printf("  %s %s\n", padRight(iconSuccess("Still today"), 14), describe(remaining))
+	} else {
+		fmt.Printf("  %s %s\n", padRight(iconSuccess("Best window"), 14), describe(best))
+	}
+	if sunrise != "" && sunset != "" {
+		fmt.Printf("  %s %s – %s\n", padRight(iconInfo("Daylight"), 14), sunrise, sunset)
+	}
+	if config.Get("chronotype") == "" && chronotype == "intermediate" {
+		printInfo("\nTip: set chronotype in your config to lark or owl for a better fit\n")
+	}
+}
+
+// bestFocusWindow returns the start of the highest-scoring run of window
+// hours beginning at or after from.
+func bestFocusWindow(scores []float64, from, window int) int {
+	best, bestScore := from, -1.0
+	for start := from; start+window <= focusLastHour+1; start++ {
+		var sum float64
+		for h := start; h < start+window; h++ {
+			sum += scores[h-focusFirstHour]
+		}
+		if sum > bestScore {
+			best, bestScore = start, sum
+		}
+	}
+	return best
+}
+
+// focusConditions interpolates the three-hourly forecast to every hour of
+// the strip.
+func focusConditions(hourly []WttrHour, sunrise, sunset string) []FocusConditions {
+	type sample struct{ hour, feelsLike float64 }
+	var samples []sample
+	for _, entry := range hourly {
+		hhmm, ok1 := entry.Time.Float()
+		feelsLike, ok2 := entry.FeelsLikeC.Float()
+		if ok1 && ok2 {
+			samples = append(samples, sample{hhmm / 100, feelsLike})
+		}
+	}
+
+	clock := func(s string, fallback int) int {
+		if t, err := time.Parse("03:04 PM", s); err == nil {
+			return t.Hour()
+		}
+		return fallback
+	}
+	rise, set := clock(sunrise, 6), clock(sunset, 18)
+
+	var conditions []FocusConditions
+	for hour := focusFirstHour; hour <= focusLastHour; hour++ {
+		feelsLike := 21.0
+		for i, s := range samples {
+			next := s
+			if i+1 < len(samples) {
+				next = samples[i+1]
+			}
+			if float64(hour) >= s.hour && (float64(hour) < next.hour || next == s) {
+				feelsLike = s.feelsLike
+				if next.hour > s.hour {
+					feelsLike += (next.feelsLike - s.feelsLike) * (float64(hour) - s.hour) / (next.hour - s.hour)
+				}
+			}
+		}
+		conditions = append(conditions, FocusConditions{Hour: hour, FeelsLike: feelsLike, Daylight: hour >= rise && hour < set})
+	}
+	return conditions
+}
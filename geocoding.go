@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"strings"
@@ -31,6 +32,8 @@ type LocationInfo struct {
 	Timezone string
 	City     string
 	Country  string
+	// TimezoneSource says how Timezone was found, for printSources
+	TimezoneSource Source
 }
 
 func getLocationInfo(query string) (*LocationInfo, error) {
@@ -40,19 +43,30 @@ func getLocationInfo(query string) (*LocationInfo, error) {
 		return nil, fmt.Errorf("geocoding failed: %v", err)
 	}
 
+	info := &LocationInfo{
+		Lat:     coords.Lat,
+		Lon:     coords.Lon,
+		City:    coords.City,
+		Country: coords.Country,
+	}
+
+	// Built-in cities know their real zone, daylight saving included. The
+	// distance check keeps a namesake elsewhere from borrowing it.
+	if city := findCity(coords.City); city != nil && distanceKm(city.Lat, city.Lon, coords.Lat, coords.Lon) < 50 {
+		info.Timezone = city.Timezone
+		info.TimezoneSource = Source{What: "Time zone", From: builtInData, Confidence: confidenceHigh}
+		return info, nil
+	}
+
 	// Then get timezone information using the coordinates
 	timezone, err := getTimezoneFromCoords(coords.Lat, coords.Lon)
 	if err != nil {
 		return nil, fmt.Errorf("timezone lookup failed: %v", err)
 	}
-
-	return &LocationInfo{
-		Lat:      coords.Lat,
-		Lon:      coords.Lon,
-		Timezone: timezone,
-		City:     coords.City,
-		Country:  coords.Country,
-	}, nil
+	info.Timezone = timezone
+	info.TimezoneSource = Source{What: "Time zone", From: "a longitude estimate", Confidence: confidenceLow,
+		Check: "it ignores borders and daylight saving, so confirm before booking a call"}
+	return info, nil
 }
 
 func geocodeAddress(query string) (*struct {
@@ -70,9 +84,7 @@ func geocodeAddress(query string) (*struct {
 	params.Add("addressdetails", "1")
 
 	// Add User-Agent header as required by Nominatim's usage policy
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := newHTTPClient(10 * time.Second)
 
 	req, err := http.NewRequest("GET", baseURL+"?"+params.Encode(), nil)
 	if err != nil {
@@ -170,3 +182,16 @@ func estimateTimezoneFromLongitude(lon float64) string {
 func parseFloat(s string) (float64, error) {
 	return json.Number(s).Float64()
 }
+
+// distanceKm returns the great-circle distance between two points.
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371
+
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -4,12 +4,22 @@ go 1.23.2
 
 require (
 	github.com/go-ping/ping v1.2.0
+	github.com/quic-go/quic-go v0.54.0
 	github.com/showwin/speedtest-go v1.7.10
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+	golang.org/x/image v0.29.0
+	golang.org/x/sys v0.34.0
+	golang.org/x/term v0.33.0
 )
 
 require (
 	github.com/google/uuid v1.6.0 // indirect
+	github.com/quic-go/qpack v0.5.1 // indirect
+	go.uber.org/mock v0.5.0 // indirect
+	golang.org/x/crypto v0.40.0 // indirect
+	golang.org/x/mod v0.25.0 // indirect
 	golang.org/x/net v0.42.0 // indirect
 	golang.org/x/sync v0.16.0 // indirect
-	golang.org/x/sys v0.34.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+	golang.org/x/tools v0.34.0 // indirect
 )
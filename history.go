@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const historyFile = "history.json"
+
+// maxHistoryEntries caps the conversion log, dropping the oldest entries.
+const maxHistoryEntries = 5000
+
+// HistoryEntry is one conversion logged by cv.
+type HistoryEntry struct {
+	Time   time.Time `json:"time"`
+	Amount float64   `json:"amount"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Rate   float64   `json:"rate"`
+}
+
+// Converted returns the amount in the target currency.
+func (e HistoryEntry) Converted() float64 {
+	return e.Amount * e.Rate
+}
+
+// recordConversion appends a conversion to the history log. Mock and
+// replay runs are not real spending, so they are left out. A log that
+// cannot be written never fails the conversion itself.
+func recordConversion(c Conversion) {
+	if options.Mock || replayer != nil {
+		return
+	}
+
+	var entries []HistoryEntry
+	if err := loadJSON(historyFile, &entries); err != nil {
+		printWarning("Warning: %v\n", err)
+		return
+	}
+	entries = append(entries, HistoryEntry{Time: time.Now(), Amount: c.Amount, From: c.From, To: c.To, Rate: c.Rate})
+	if len(entries) > maxHistoryEntries {
+		entries = entries[len(entries)-maxHistoryEntries:]
+	}
+	if err := saveJSON(historyFile, entries); err != nil {
+		printWarning("Warning: %v\n", err)
+	}
+}
+
+// handleHistory implements `nomad cv history [--since DATE] [--until DATE]
+// [--pair FROM/TO] [--clear]`.
+func handleHistory(args []string) {
+	args, since, _ := popFlagValue(args, "--since")
+	args, until, _ := popFlagValue(args, "--until")
+	args, pair, _ := popFlagValue(args, "--pair")
+	args, clear := popFlag(args, "--clear")
+
+	if len(args) > 0 {
+		printError("Usage: nomad cv history [--since YYYY-MM-DD] [--until YYYY-MM-DD] [--pair FROM/TO] [--clear]\n")
+		printInfo("Example: nomad cv history --since 2025-01-01 --pair thb/aud\n")
+		os.Exit(1)
+	}
+
+	if clear {
+		if err := saveJSON(historyFile, []HistoryEntry{}); err != nil {
+			printError("Error: %v\n", err)
+			os.Exit(1)
+		}
+		printSuccess("Conversion history cleared\n")
+		return
+	}
+
+	var start, end time.Time
+	for _, bound := range []struct {
+		text string
+		date *time.Time
+	}{{since, &start}, {until, &end}} {
+		if bound.text == "" {
+			continue
+		}
+		date, err := time.ParseInLocation("2006-01-02", bound.text, time.Local)
+		if err != nil {
+			printError("Error: Invalid date '%s' (use YYYY-MM-DD)\n", bound.text)
+			os.Exit(1)
+		}
+		*bound.date = date
+	}
+	if !end.IsZero() {
+		// --until includes the whole day
+		end = end.AddDate(0, 0, 1)
+	}
+
+	var from, to string
+	if pair != "" {
+		var ok bool
+		from, to, ok = strings.Cut(pair, "/")
+		if !ok {
+			printError("Error: Invalid pair '%s' (use FROM/TO, e.g. thb/aud)\n", pair)
+			os.Exit(1)
+		}
+		from, to = mustResolveCurrency(from), mustResolveCurrency(to)
+	}
+
+	var entries []HistoryEntry
+	if err := loadJSON(historyFile, &entries); err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var matches []HistoryEntry
+	for _, entry := range entries {
+		if (!start.IsZero() && entry.Time.Before(start)) || (!end.IsZero() && !entry.Time.Before(end)) {
+			continue
+		}
+		if pair != "" && (entry.From != from || entry.To != to) {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+
+	if len(matches) == 0 {
+		printInfo("No conversions found\n")
+		return
+	}
+
+	fmt.Println()
+	printTitle("%s Conversion History\n", iconCurrency(""))
+
+	table := NewTable("Date", "Amount", "Converted", "Rate")
+	var totalFrom, totalTo float64
+	for _, entry := range matches {
+		table.AddRow(
+			colorCyan(entry.Time.Local().Format("Jan 2, 15:04")),
+			formatAmount(entry.Amount, entry.From)+" "+entry.From,
+			colorGreen(formatAmount(entry.Converted(), entry.To)+" "+entry.To),
+			formatRate(entry.Rate),
+		)
+		totalFrom += entry.Amount
+		totalTo += entry.Converted()
+	}
+	table.Print()
+
+	fmt.Println()
+	if pair != "" {
+		fmt.Printf("  %s %s %s = %s %s\n", padRight(iconSuccess("Total"), 14),
+			formatAmount(totalFrom, from), from, colorGreen(formatAmount(totalTo, to)), to)
+	}
+	printInfo("  %d conversion(s)\n", len(matches))
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// sharedTransport is used by every HTTP client created during a single
+// invocation so that connections and TLS sessions to the same API host are
+// kept alive and reused between calls.
+var sharedTransport = &http.Transport{
+	Proxy:                 http.ProxyFromEnvironment,
+	DialContext:           dialCached,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          20,
+	MaxIdleConnsPerHost:   4,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+var (
+	clientTransportOnce sync.Once
+	clientTransport     http.RoundTripper
+)
+
+// newHTTPClient returns a client with the given overall timeout that shares
+// the process-wide transport.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	clientTransportOnce.Do(func() {
+		clientTransport = sharedTransport
+		if options.HTTP3 {
+			clientTransport = &fallbackTransport{
+				primary:  &http3.Transport{Dial: dialQUIC},
+				fallback: sharedTransport,
+			}
+		}
+		clientTransport = &statusTransport{next: clientTransport}
+		if options.Verbose {
+			clientTransport = &verboseTransport{next: clientTransport}
+		}
+		if options.RecordDir != "" {
+			clientTransport = &recordingTransport{next: clientTransport, dir: options.RecordDir}
+		}
+		if replayer != nil {
+			clientTransport = replayer
+		}
+	})
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: clientTransport,
+	}
+}
+
+var dialer = &net.Dialer{
+	Timeout:   10 * time.Second,
+	KeepAlive: 30 * time.Second,
+}
+
+// dnsCacheTTL is how long a resolved address is reused, so long-running
+// commands like `cv watch` notice when a host moves.
+const dnsCacheTTL = 5 * time.Minute
+
+// dnsEntry holds the result of a single hostname lookup. done is closed
+// once addrs, err and expires are set.
+type dnsEntry struct {
+	done    chan struct{}
+	addrs   []string
+	err     error
+	expires time.Time
+}
+
+var (
+	dnsMu    sync.Mutex
+	dnsCache = make(map[string]*dnsEntry)
+)
+
+// resolveHost looks up host at most once every dnsCacheTTL. Concurrent
+// callers for the same host wait for the lookup already in flight. Failed
+// lookups aren't kept, so the next request tries again.
+func resolveHost(ctx context.Context, host string) ([]string, error) {
+	dnsMu.Lock()
+	entry, ok := dnsCache[host]
+	if ok && isDone(entry.done) && time.Now().After(entry.expires) {
+		ok = false
+	}
+	if !ok {
+		entry = &dnsEntry{done: make(chan struct{})}
+		dnsCache[host] = entry
+		go func() {
+			lookupCtx, cancel := context.WithTimeout(context.Background(), dialer.Timeout)
+			defer cancel()
+			entry.addrs, entry.err = lookupHost(lookupCtx, host)
+			entry.expires = time.Now().Add(dnsCacheTTL)
+			if entry.err != nil {
+				dnsMu.Lock()
+				if dnsCache[host] == entry {
+					delete(dnsCache, host)
+				}
+				dnsMu.Unlock()
+			}
+			close(entry.done)
+		}()
+	}
+	dnsMu.Unlock()
+
+	select {
+	case <-entry.done:
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		return filterAddrs(entry.addrs, options.IPFamily), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// isDone reports whether done has been closed.
+func isDone(done chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// filterAddrs keeps only the addresses of the preferred IP family. An empty
+// family keeps everything.
+func filterAddrs(addrs []string, family string) []string {
+	if family == "" {
+		return addrs
+	}
+
+	var filtered []string
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+		if isIPv4 := ip.To4() != nil; isIPv4 == (family == "4") {
+			filtered = append(filtered, addr)
+		}
+	}
+	return filtered
+}
+
+// lookupHost resolves host with DNS-over-HTTPS when enabled, or the system
+// resolver otherwise.
+func lookupHost(ctx context.Context, host string) ([]string, error) {
+	if options.DoH {
+		return lookupHostDoH(ctx, options.DoHProvider, host)
+	}
+	return net.DefaultResolver.LookupHost(ctx, host)
+}
+
+// warmupDNS starts resolving hosts in the background so that the lookups
+// overlap with argument parsing and other requests instead of adding a full
+// round trip to each API call.
+func warmupDNS(hosts ...string) {
+	for _, host := range hosts {
+		go resolveHost(context.Background(), host)
+	}
+}
+
+// dialCached dials addr using the per-invocation DNS cache, trying each
+// resolved address in turn.
+func dialCached(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	addrs, err := resolveHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, noAddrError(host)
+	}
+
+	var lastErr error
+	for _, ip := range addrs {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// dialQUIC opens an HTTP/3 connection using the shared DNS cache, so the
+// IP family preference applies to QUIC as well.
+func dialQUIC(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (*quic.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := []string{host}
+	if net.ParseIP(host) == nil {
+		if addrs, err = resolveHost(ctx, host); err != nil {
+			return nil, err
+		}
+		if len(addrs) == 0 {
+			return nil, noAddrError(host)
+		}
+	}
+
+	var lastErr error
+	for _, ip := range addrs {
+		conn, err := quic.DialAddrEarly(ctx, net.JoinHostPort(ip, port), tlsCfg, cfg)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func noAddrError(host string) error {
+	if options.IPFamily != "" {
+		return fmt.Errorf("no IPv%s addresses found for %s", options.IPFamily, host)
+	}
+	return fmt.Errorf("no addresses found for %s", host)
+}
+
+// fallbackTransport tries HTTP/3 first and falls back to TCP when QUIC is
+// blocked or unsupported. Hosts that fail over QUIC are not retried with it
+// for the rest of the invocation.
+type fallbackTransport struct {
+	primary  http.RoundTripper
+	fallback http.RoundTripper
+
+	mu      sync.Mutex
+	tcpOnly map[string]bool
+}
+
+func (t *fallbackTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	skip := t.tcpOnly[req.URL.Host]
+	t.mu.Unlock()
+
+	// Requests with a body cannot be replayed, so only idempotent calls
+	// are attempted over QUIC
+	if skip || req.Body != nil {
+		return t.fallback.RoundTrip(req)
+	}
+
+	resp, err := t.primary.RoundTrip(req)
+	if err == nil {
+		return resp, nil
+	}
+
+	logVerbose("HTTP/3 to %s failed (%v), falling back to TCP", req.URL.Host, err)
+	t.mu.Lock()
+	if t.tcpOnly == nil {
+		t.tcpOnly = make(map[string]bool)
+	}
+	t.tcpOnly[req.URL.Host] = true
+	t.mu.Unlock()
+
+	return t.fallback.RoundTrip(req)
+}
+
+// verboseTransport logs the protocol, remote address and duration of every
+// request.
+type verboseTransport struct {
+	next http.RoundTripper
+}
+
+func (t *verboseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var remote string
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			remote = info.Conn.RemoteAddr().String()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil && remote == "" {
+				remote = addr
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		logVerbose("%s %s failed after %v: %v", req.Method, sanitizeURL(req.URL), elapsed, err)
+		return nil, err
+	}
+
+	logVerbose("%s %s -> %s via %s [%s] in %v", req.Method, sanitizeURL(req.URL), resp.Status, resp.Proto, remote, elapsed)
+	return resp, nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Where a resolved location came from.
+const (
+	locationFromArgs  = "argument"
+	locationFromHome  = "home"
+	locationFromSaved = "saved"
+	locationFromIP    = "ip"
+)
+
+// resolveLocation applies the location chain shared by every command that
+// works on a place: an explicit argument, then home_city from the config,
+// then the IP-based location (an empty query). --here forces the IP-based
+// location and --home the home city. Callers pop their own flags first, so
+// whatever is left names the place: "here", "home", a place saved with
+// `nomad places add`, or anything the provider understands. It returns the
+// query and where it came from.
+func resolveLocation(args []string) (string, string) {
+	args, here := popFlag(args, "--here")
+	args, home := popFlag(args, "--home")
+	if here && home {
+		printError("Error: --here and --home cannot be used together\n")
+		os.Exit(1)
+	}
+
+	homeCity := strings.TrimSpace(config.Get("home_city"))
+	if home {
+		if homeCity == "" {
+			printError("Error: --home needs home_city in your config\n")
+			os.Exit(1)
+		}
+		return homeCity, locationFromHome
+	}
+	if here {
+		return "", locationFromIP
+	}
+
+	query := strings.Join(args, " ")
+	switch strings.ToLower(query) {
+	case "":
+	case "here":
+		return "", locationFromIP
+	case "home":
+		if homeCity == "" {
+			printError("Error: 'home' needs home_city in your config\n")
+			os.Exit(1)
+		}
+		return homeCity, locationFromHome
+	default:
+		if saved, ok := lookupPlace(query); ok {
+			return saved, locationFromSaved
+		}
+		return query, locationFromArgs
+	}
+	if homeCity != "" {
+		return homeCity, locationFromHome
+	}
+	return "", locationFromIP
+}
+
+// hereQuery returns "City, Country" for the IP-based location, for
+// providers that cannot locate the caller themselves.
+func hereQuery() (string, error) {
+	response, err := wttr.Forecast("")
+	if err != nil {
+		return "", err
+	}
+	area, ok := response.Area()
+	if !ok {
+		return "", fmt.Errorf("no location in the weather response")
+	}
+
+	city, country := area.AreaName.String(), area.Country.String()
+	switch {
+	case city != "" && country != "":
+		rememberPlace(city+", "+country, country)
+		return city + ", " + country, nil
+	case city != "":
+		return city, nil
+	}
+	return "", fmt.Errorf("no location in the weather response")
+}
+
+// ipLocateURL answers with the caller's location from their IP address.
+const ipLocateURL = "https://ipwho.is/"
+
+// hereWithoutWttr returns "City, Country" for the IP-based location
+// without wttr.in, for the weather providers standing in while it is down:
+// a recently remembered place, then ipwho.is, then whatever place or
+// country was remembered last.
+func hereWithoutWttr() (string, error) {
+	var state locationState
+	if err := loadJSON(locationStateFile, &state); err != nil {
+		logVerbose("Ignoring remembered location: %v", err)
+	}
+	if state.Place != "" && time.Since(state.SeenAt) < locationFreshness && !options.Mock {
+		return state.Place, nil
+	}
+
+	place, err := ipLocate()
+	if err == nil {
+		return place, nil
+	}
+	logVerbose("IP geolocation failed: %v", err)
+	switch {
+	case options.Mock:
+	case state.Place != "":
+		return state.Place, nil
+	case state.Country != "":
+		return state.Country, nil
+	}
+	return "", err
+}
+
+// ipLocate asks ipwho.is where the caller is.
+func ipLocate() (string, error) {
+	resp, err := newHTTPClient(10 * time.Second).Get(ipLocateURL)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &statusError{Status: resp.StatusCode}
+	}
+
+	var response struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		City    string `json:"city"`
+		Country string `json:"country"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", schemaMismatch("ipwho.is", "invalid JSON: "+err.Error(), body)
+	}
+	if !response.Success {
+		return "", fmt.Errorf("ipwho.is: %s", response.Message)
+	}
+	if response.City == "" || response.Country == "" {
+		return "", schemaMismatch("ipwho.is", "no city or country", body)
+	}
+
+	place := response.City + ", " + response.Country
+	rememberPlace(place, response.Country)
+	return place, nil
+}
+
+// geocodedFile remembers where places looked up by geocodePlace are, so
+// commands using it keep working offline.
+const geocodedFile = "geocoded.json"
+
+// geocodePlace finds the coordinates and time zone of query without the
+// network where it can: coordinates as given, the built-in cities, then
+// places geocoded on an earlier run.
+func geocodePlace(query string) (*LocationInfo, error) {
+	if lat, lon, ok := parseCoordinates(query); ok {
+		return &LocationInfo{Lat: lat, Lon: lon, City: formatCoordinates(lat, lon),
+			Timezone: estimateTimezoneFromLongitude(lon),
+			TimezoneSource: Source{What: "Time zone", From: "a longitude estimate", Confidence: confidenceLow,
+				Check: "it ignores borders and daylight saving, so check local times against a clock there"}}, nil
+	}
+
+	name, _, _ := strings.Cut(query, ",")
+	if city := findCity(strings.TrimSpace(name)); city != nil {
+		return &LocationInfo{Lat: city.Lat, Lon: city.Lon, City: city.Name, Country: city.Country, Timezone: city.Timezone,
+			TimezoneSource: Source{What: "Time zone", From: builtInData, Confidence: confidenceHigh}}, nil
+	}
+
+	key := strings.ToLower(strings.TrimSpace(query))
+	geocoded := make(map[string]*LocationInfo)
+	if err := loadJSON(geocodedFile, &geocoded); err != nil {
+		logVerbose("Ignoring geocoded places: %v", err)
+	}
+	if location, ok := geocoded[key]; ok {
+		return location, nil
+	}
+
+	location, err := getLocationInfo(query)
+	if err != nil || options.Mock {
+		return location, err
+	}
+	geocoded[key] = location
+	if err := saveJSON(geocodedFile, geocoded); err != nil {
+		logVerbose("Could not remember %s: %v", query, err)
+	}
+	return location, nil
+}
+
+// parseCoordinates reads a "lat,lon" query such as "13.75,100.50", for
+// places with no useful name like anchorages and campsites.
+func parseCoordinates(query string) (float64, float64, bool) {
+	latText, lonText, ok := strings.Cut(query, ",")
+	if !ok {
+		return 0, 0, false
+	}
+	lat, latErr := parseFloat(strings.TrimSpace(latText))
+	lon, lonErr := parseFloat(strings.TrimSpace(lonText))
+	if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// formatCoordinates shows a point the way maps apps do, e.g. "13.7500°N,
+// 100.5000°E".
+func formatCoordinates(lat, lon float64) string {
+	ns, ew := "N", "E"
+	if lat < 0 {
+		ns, lat = "S", -lat
+	}
+	if lon < 0 {
+		ew, lon = "W", -lon
+	}
+	return fmt.Sprintf("%.4f°%s, %.4f°%s", lat, ns, lon, ew)
+}
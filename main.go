@@ -1,66 +1,125 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 )
 
-type ExchangeRateResponse struct {
-	Rates map[string]float64 `json:"rates"`
-	Base  string             `json:"base"`
-	Date  string             `json:"date"`
+// commandHosts lists the API hostnames each command contacts, so their DNS
+// lookups can be started up front.
+var commandHosts = map[string][]string{
+	"cv":       {"api.exchangerate-api.com", "api.coingecko.com"},
+	"convert":  {"api.exchangerate-api.com", "api.coingecko.com"},
+	"price":    {"api.exchangerate-api.com"},
+	"subs":     {"api.exchangerate-api.com"},
+	"w":        {"wttr.in"},
+	"weather":  {"wttr.in"},
+	"t":        {"nominatim.openstreetmap.org"},
+	"time":     {"nominatim.openstreetmap.org"},
+	"airport":  {"wttr.in"},
+	"focus":    {"wttr.in"},
+	"umbrella": {"wttr.in"},
+	"baggage":  {"api.exchangerate-api.com"},
+	"ticker":   {"api.coingecko.com", "query1.finance.yahoo.com", "api.exchangerate-api.com"},
 }
 
 func main() {
-	if len(os.Args) < 2 {
+	cfg, err := loadConfig()
+	if err != nil {
+		printWarning("Warning: %v\n", err)
+	} else {
+		config = cfg
+	}
+
+	// Global flags may appear anywhere, so strip them before dispatching
+	args := parseGlobalOptions(os.Args[1:])
+
+	if len(args) < 1 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
+	if options.RecordDir != "" {
+		if err := startRecording(options.RecordDir, args); err != nil {
+			printError("Error: %v\n", err)
+			os.Exit(1)
+		}
+		printInfo("Recording API responses to %s\n", options.RecordDir)
+	}
+
+	if options.Mock {
+		replayer = mockTransport{}
+	}
+
+	command := args[0]
+
+	// Resolve the API hosts this command talks to while it gets going
+	if !options.Mock {
+		warmupDNS(commandHosts[command]...)
+	}
+
+	// Single values for scripts must stay undecorated
+	if command != "fact" && !options.NDJSON && !containsString(args, "--json") && !containsString(args, "--format") {
+		showCountryHint()
+	}
+
+	runCommand(command, args[1:])
+	waitForSpeech()
+}
 
+// runCommand dispatches a command and its arguments to its handler.
+func runCommand(command string, args []string) {
 	switch command {
 	case "cv", "convert":
-		if len(os.Args) < 5 {
-			printError("Usage: nomad cv <amount> <from_currency> <to_currency>\n")
-			printInfo("Example: nomad cv 1000 thb aud\n")
-			os.Exit(1)
-		}
-		handleCurrencyConversion(os.Args[2:])
+		handleCurrencyConversion(args)
+	case "price":
+		handlePrice(args)
+	case "subs", "subscriptions":
+		handleSubscriptions(args)
 	case "w", "weather":
-		// City is optional - if not provided, will use IP-based location
-		var args []string
-		if len(os.Args) >= 3 {
-			args = os.Args[2:]
-		} else {
-			args = []string{} // Empty args will trigger IP-based location
-		}
+		// City is optional - empty args will trigger IP-based location
 		HandleWeather(args)
 	case "t", "time":
-		if len(os.Args) < 3 {
-			printError("Usage: nomad time <city or address>\n")
-			printInfo("Example: nomad time Tokyo\n")
-			printInfo("Example: nomad time \"123 Main St, New York, NY\"\n")
-			os.Exit(1)
-		}
-		HandleTime(os.Args[2:])
+		HandleTime(args)
 
 	case "s", "speed", "speedtest":
-		handleSpeedTest()
+		handleSpeedTest(args)
 	case "p", "ping":
 		handlePing()
 	case "v", "visa":
-		handleVisa(os.Args[2:])
+		handleVisa(args)
 	case "f", "flight":
-		handleFlight(os.Args[2:])
+		handleFlight(args)
+	case "airport":
+		handleAirport(args)
+	case "baggage":
+		handleBaggage(args)
+	case "focus":
+		handleFocus(args)
+	case "umbrella":
+		handleUmbrella(args)
+	case "sun":
+		handleSun(args)
+	case "places":
+		handlePlaces(args)
+	case "ticker":
+		handleTicker(args)
+	case "drive":
+		handleDrive(args)
+	case "safe":
+		handleSafe(args)
+	case "doctor":
+		handleDoctor()
+	case "providers":
+		handleProviders()
+	case "fact":
+		handleFact(args)
+	case "replay":
+		handleReplay(args)
 	case "help", "-h", "--help":
 		printUsage()
 	default:
@@ -75,101 +134,74 @@ func printUsage() {
 	fmt.Println()
 	printInfo("Commands:\n")
 	fmt.Printf("  %s    %s\n", iconCurrency(colorBold("cv, convert")), "Convert currency")
+	fmt.Printf("  %s    %s\n", iconCurrency(colorBold("price")), "Normalize a listing price to nightly, weekly and monthly figures")
+	fmt.Printf("  %s    %s\n", iconCurrency(colorBold("subs")), "Track recurring subscriptions in your home currency [add|rm]")
 	fmt.Printf("  %s    %s\n", iconWeather(colorBold("w, weather")), "Get weather information (auto-location or specify city)")
+	fmt.Printf("  %s    %s\n", iconWeather(colorBold("umbrella")), "Whether rain is likely in the next 12 hours, in one line [city]")
+	fmt.Printf("  %s    %s\n", iconWeather(colorBold("sun")), "Sunrise, sunset, golden hours and day length, worked out offline [city]")
 	fmt.Printf("  %s    %s\n", iconTime(colorBold("t, time")), "Get current time in different timezones")
+	fmt.Printf("  %s    %s\n", iconTime(colorBold("focus")), "Today's best deep-work window from the forecast and your chronotype [city]")
+	fmt.Printf("  %s    %s\n", iconLocation(colorBold("places")), "Save named places to use in weather, time and focus [add|rm]")
 	fmt.Printf("  %s    %s\n", iconSpeed(colorBold("s, speed")), "Test network speed and quality")
 	fmt.Printf("  %s    %s\n", iconLatency(colorBold("p, ping")), "Ping a list of servers to check latency")
+	fmt.Printf("  %s    %s\n", iconCurrency(colorBold("ticker")), "Crypto and stock prices with the change and value in your currency [symbols...]")
 	fmt.Printf("  %s    %s\n", iconInfo(colorBold("v, visa")), "Get visa information for a destination country [nationality] [destination]")
 	fmt.Printf("  %s    %s\n", iconInfo(colorBold("f, flight")), "Search for flight information [flight_number]")
+	fmt.Printf("  %s    %s\n", iconInfo(colorBold("fact")), "Print a single value for scripts and shortcuts [weather.temp|rate.usd.thb|time.tokyo]")
+	fmt.Printf("  %s    %s\n", iconLocation(colorBold("airport")), "Airport local time, distance, transit and weather [IATA code]")
+	fmt.Printf("  %s    %s\n", iconInfo(colorBold("baggage")), "Airline baggage allowances and typical fees in your currency [--route BKK-DPS]")
+	fmt.Printf("  %s    %s\n", iconLocation(colorBold("drive")), "Driving side, permit, speed and alcohol limits for a country [--license de]")
+	fmt.Printf("  %s    %s\n", iconInfo(colorBold("safe")), "Tap water, ice and food safety for a country")
+	fmt.Printf("  %s    %s\n", iconInfo(colorBold("doctor")), "Check the environment and connectivity, with suggested fixes")
+	fmt.Printf("  %s    %s\n", iconNetwork(colorBold("providers")), "Show which API providers are answering, their latency and fallbacks")
+	fmt.Printf("  %s    %s\n", iconInfo(colorBold("replay")), "Re-run a command recorded with --record [dir]")
 	fmt.Printf("  %s    %s\n", iconInfo(colorBold("help")), "Show this help message")
 	fmt.Println()
+	printInfo("Global options:\n")
+	fmt.Printf("  %s    %s\n", colorBold("--ipv4, --ipv6"), "Only connect over one IP address family")
+	fmt.Printf("  %s          %s\n", colorBold("--http3"), "Use HTTP/3 (QUIC) where supported, falling back to TCP")
+	fmt.Printf("  %s        %s\n", colorBold("--verbose"), "Show protocol, address and timing of each request")
+	fmt.Printf("  %s            %s\n", colorBold("--doh"), "Resolve API hosts with DNS-over-HTTPS (--doh-provider cloudflare|google|URL)")
+	fmt.Printf("  %s   %s\n", colorBold("--record <dir>"), "Save sanitized API responses for a bug report")
+	fmt.Printf("  %s          %s\n", colorBold("--plain"), "Screen reader friendly output: main result first, no icons or colors")
+	fmt.Printf("  %s          %s\n", colorBold("--speak"), "Read the main result aloud")
+	fmt.Printf("  %s %s\n", colorBold("--provider <name>"), "Exchange rate source: exchangerate-api, frankfurter, openexchangerates, fixer")
+	fmt.Printf("  %s %s\n", colorBold("--weather-provider <name>"), "Weather source for the weather command: wttr, open-meteo, openweathermap")
+	fmt.Printf("  %s         %s\n", colorBold("--ndjson"), "Stream one JSON event per sample from ping and cv watch")
+	fmt.Printf("  %s %s\n", colorBold("--units imperial"), "Temperatures in °F, wind in mph and distances in miles")
+	fmt.Printf("  %s        %s\n", colorBold("--sources"), "Show where each part of a result came from, how current and how reliable")
+	fmt.Printf("  %s       %s\n", colorBold("--no-pager"), "Print long lists straight to the terminal instead of paging them")
+	fmt.Printf("  %s           %s\n", colorBold("--mock"), "Use bundled sample data instead of the network (or NOMAD_MOCK=1)")
+	fmt.Println()
 	printInfo("Examples:\n")
 	fmt.Printf("  %s\n", colorCyan("nomad-cli convert 50 usd eur"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli cv trend usd thb --days 30"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli cv compare 1000 usd thb"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli price 2400 thb --per night --days 30 --to usd"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli subs add netflix 16.99 usd monthly"))
 	fmt.Printf("  %s\n", colorCyan("nomad-cli weather"))
 	fmt.Printf("  %s\n", colorCyan("nomad-cli weather London"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli weather Niseko --snow"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli weather Lisbon --art"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli weather Lisbon --detail"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli weather --all"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli weather rank"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli weather 13.75,100.50"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli weather Lisbon --on 2026-03-10"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli weather Medellin --climate"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli weather --format \"%c %t (%f) %w\""))
 	fmt.Printf("  %s\n", colorCyan("nomad-cli time Tokyo"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli time Lisbon --open-map"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli time --all"))
 	fmt.Printf("  %s\n", colorCyan("nomad-cli speed"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli speed --card speed.png"))
 	fmt.Printf("  %s\n", colorCyan("nomad-cli ping"))
 	fmt.Printf("  %s\n", colorCyan("nomad-cli visa au th"))
 	fmt.Printf("  %s\n", colorCyan("nomad-cli flight tg413"))
-}
-
-func handleCurrencyConversion(args []string) {
-	// Parse command line arguments
-	amountStr := args[0]
-	fromCurrency := strings.ToUpper(args[1])
-	toCurrency := strings.ToUpper(args[2])
-
-	// Convert amount to float
-	amount, err := strconv.ParseFloat(amountStr, 64)
-	if err != nil {
-		printError("Error: Invalid amount '%s'\n", amountStr)
-		os.Exit(1)
-	}
-
-	// Validate currencies
-	if len(fromCurrency) != 3 || len(toCurrency) != 3 {
-		printError("Error: Currency codes must be 3 letters (e.g., USD, EUR, THB, AUD)\n")
-		os.Exit(1)
-	}
-
-	// Get exchange rate with loading spinner
-	var rate float64
-	err = WithSpinner("Fetching exchange rates...", func() error {
-		var fetchErr error
-		rate, fetchErr = getExchangeRate(fromCurrency, toCurrency)
-		return fetchErr
-	})
-
-	if err != nil {
-		printError("Error getting exchange rate: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Calculate converted amount
-	convertedAmount := amount * rate
-
-	// Display result with better formatting
-	fmt.Println()
-	printTitle("%s Currency Conversion\n", iconCurrency(""))
-	fmt.Printf("  %-12s %.2f %s = %.2f %s\n", iconSuccess(""), amount, fromCurrency, convertedAmount, toCurrency)
-	fmt.Printf("  %-12s 1 %s = %.4f %s\n", iconInfo(""), fromCurrency, rate, toCurrency)
-}
-
-func getExchangeRate(fromCurrency, toCurrency string) (float64, error) {
-	// Using exchangerate-api.com (free tier)
-	url := fmt.Sprintf("https://api.exchangerate-api.com/v4/latest/%s", fromCurrency)
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return 0, fmt.Errorf("failed to fetch exchange rate: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("API returned status code: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	var response ExchangeRateResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return 0, fmt.Errorf("failed to parse JSON response: %v", err)
-	}
-
-	rate, exists := response.Rates[toCurrency]
-	if !exists {
-		return 0, fmt.Errorf("currency '%s' not found in exchange rates", toCurrency)
-	}
-
-	return rate, nil
+	fmt.Printf("  %s\n", colorCyan("nomad-cli airport BKK"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli baggage airasia --route BKK-DPS"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli drive thailand --license de"))
+	fmt.Printf("  %s\n", colorCyan("nomad-cli fact rate.usd.thb"))
 }
 
 // Helper function to get keys from a map
@@ -209,12 +241,14 @@ func handleTime(args []string) {
 	// Display time information with better formatting
 	fmt.Println()
 	printTitle("%s Current time in %s\n", iconTime(""), location.City)
-	fmt.Printf("  %-12s %s\n", iconTime("Time · "), colorYellow(now.Format("Mon, Jan 2, 2006 3:04 PM MST")))
-	// fmt.Printf("  %-12s %s\n", iconInfo(" Timezone"), colorCyan(location.Timezone))
-	// fmt.Printf("  %-12s %s, %s\n", iconLocation("Location"), location.City, location.Country)
+	fmt.Printf("  %s %s\n", padRight(iconTime("Time · "), 14), colorYellow(now.Format("Mon, Jan 2, 2006 3:04 PM MST")))
+	// fmt.Printf("  %s %s\n", padRight(iconInfo(" Timezone"), 14), colorCyan(location.Timezone))
+	// fmt.Printf("  %s %s, %s\n", padRight(iconLocation("Location"), 14), location.City, location.Country)
 }
 
-func handleSpeedTest() {
+func handleSpeedTest(args []string) {
+	_, cardPath, _ := popFlagValue(args, "--card")
+
 	// Run the comprehensive speed test
 	result, quality, err := RunSpeedTest()
 	if err != nil {
@@ -222,18 +256,20 @@ func handleSpeedTest() {
 		os.Exit(1)
 	}
 
+	announceResult("Download %s, upload %s, latency %s", formatSpeed(result.DownloadSpeed), formatSpeed(result.UploadSpeed), formatLatency(result.Latency))
+
 	// Display results
 	fmt.Println()
 	printTitle("%s Speed Test Results\n", iconSpeed(""))
 
 	// Server information
-	fmt.Printf("  %-12s %s (%s)\n", iconInfo("Server"), colorCyan(result.ServerName), colorCyan(result.ServerCountry))
+	fmt.Printf("  %s %s (%s)\n", padRight(iconInfo("Server"), 14), colorCyan(result.ServerName), colorCyan(result.ServerCountry))
 
 	// Basic metrics
-	fmt.Printf("  %-12s %s\n", iconLatency("Latency"), colorYellow(formatLatency(result.Latency)))
-	fmt.Printf("  %-12s %s\n", iconJitter("Jitter"), colorYellow(formatLatency(result.Jitter)))
-	fmt.Printf("  %-12s %s\n", iconDownload("Download"), colorGreen(formatSpeed(result.DownloadSpeed)))
-	fmt.Printf("  %-12s %s\n", iconUpload("Upload"), colorBlue(formatSpeed(result.UploadSpeed)))
+	fmt.Printf("  %s %s\n", padRight(iconLatency("Latency"), 14), colorYellow(formatLatency(result.Latency)))
+	fmt.Printf("  %s %s\n", padRight(iconJitter("Jitter"), 14), colorYellow(formatLatency(result.Jitter)))
+	fmt.Printf("  %s %s\n", padRight(iconDownload("Download"), 14), colorGreen(formatSpeed(result.DownloadSpeed)))
+	fmt.Printf("  %s %s\n", padRight(iconUpload("Upload"), 14), colorBlue(formatSpeed(result.UploadSpeed)))
 
 	// Network quality scores
 	fmt.Println()
@@ -243,15 +279,38 @@ func handleSpeedTest() {
 	gamingColor := getQualityColor(quality.Gaming)
 	webchatColor := getQualityColor(quality.Webchat)
 
-	fmt.Printf("  %-12s %s\n", iconInfo("Streaming"), streamingColor(quality.Streaming))
-	fmt.Printf("  %-12s %s\n", iconInfo("Gaming"), gamingColor(quality.Gaming))
-	fmt.Printf("  %-12s %s\n", iconInfo("Webchat/RTC"), webchatColor(quality.Webchat))
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Streaming"), 14), streamingColor(quality.Streaming))
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Gaming"), 14), gamingColor(quality.Gaming))
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Webchat/RTC"), 14), webchatColor(quality.Webchat))
+
+	saveCard(NewCard("Speed Test Results").
+		Add("Server", fmt.Sprintf("%s (%s)", result.ServerName, result.ServerCountry)).
+		Add("Latency", formatLatency(result.Latency)).
+		Add("Jitter", formatLatency(result.Jitter)).
+		Add("Download", formatSpeed(result.DownloadSpeed)).
+		Add("Upload", formatSpeed(result.UploadSpeed)).
+		Add("Streaming", quality.Streaming).
+		Add("Gaming", quality.Gaming).
+		Add("Webchat/RTC", quality.Webchat), cardPath)
 }
 
 func handlePing() {
+	if options.NDJSON {
+		RunPingTests(func(result PingResult) {
+			fields := map[string]interface{}{"server": result.Server.Name, "address": result.Server.Address}
+			if result.Error != nil {
+				fields["error"] = result.Error.Error()
+			} else {
+				fields["latency_ms"] = float64(result.Latency.Microseconds()) / 1000
+			}
+			emitEvent("ping", fields)
+		})
+		return
+	}
+
 	var results []PingResult
 	err := WithSpinner("Pinging servers...", func() error {
-		results = RunPingTests()
+		results = RunPingTests(nil)
 		return nil
 	})
 
@@ -274,9 +333,11 @@ func handlePing() {
 	fmt.Println()
 	printTitle("%s Ping Results\n", iconLatency(""))
 
+	// Long server locations wrap; so do connection errors in place of a latency
+	table := NewTable("Server", "Latency").SetMaxWidth(0, 28).SetMaxWidth(1, 40)
 	for _, result := range results {
 		if result.Error != nil {
-			printError("  %-20s %s\n", result.Server.Name, result.Error)
+			table.AddRow(result.Server.Name, colorRed(result.Error.Error()))
 		} else {
 			latencyMs := result.Latency.Milliseconds()
 			var colorFunc func(string) string
@@ -287,14 +348,16 @@ func handlePing() {
 			} else {
 				colorFunc = colorRed
 			}
-			fmt.Printf("  %-20s %s\n", result.Server.Name, colorFunc(result.Latency.String()))
+			table.AddRow(result.Server.Name, colorFunc(result.Latency.String()))
 		}
 	}
+	table.Print()
 }
 
 func handleVisa(args []string) {
+	args, asQR := popFlag(args, "--qr")
 	if len(args) < 2 {
-		printError("Usage: nomad-cli visa <nationality_country_code> <destination_country_code>\n")
+		printError("Usage: nomad-cli visa <nationality_country_code> <destination_country_code> [--qr]\n")
 		printInfo("Example: nomad-cli visa au th (for Australian citizens traveling to Thailand)\n")
 		os.Exit(1)
 	}
@@ -305,7 +368,7 @@ func handleVisa(args []string) {
 	url := GenerateVisaLink(nationality, destination)
 
 	printInfo("Opening visa information for %s citizens traveling to %s...\n", strings.ToUpper(nationality), strings.ToUpper(destination))
-	err := OpenBrowser(url)
+	err := openLink(url, asQR)
 	if err != nil {
 		printError("Error opening browser: %v\n", err)
 		os.Exit(1)
@@ -313,8 +376,9 @@ func handleVisa(args []string) {
 }
 
 func handleFlight(args []string) {
+	args, asQR := popFlag(args, "--qr")
 	if len(args) < 1 {
-		printError("Usage: nomad-cli flight <flight_number>\n")
+		printError("Usage: nomad-cli flight <flight_number> [--qr]\n")
 		printInfo("Example: nomad-cli flight tg413\n")
 		os.Exit(1)
 	}
@@ -323,7 +387,7 @@ func handleFlight(args []string) {
 	searchURL := fmt.Sprintf("https://www.google.com/search?q=%s", url.QueryEscape(flightNumber))
 
 	printInfo("Searching for flight %s...\n", strings.ToUpper(flightNumber))
-	err := OpenBrowser(searchURL)
+	err := openLink(searchURL, asQR)
 	if err != nil {
 		printError("Error opening browser: %v\n", err)
 		os.Exit(1)
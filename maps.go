@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"runtime"
+	"strings"
+)
+
+// mapProvider returns the maps service to link to, from the map_provider
+// config key. Apple Maps is the default on macOS, OpenStreetMap elsewhere.
+func mapProvider() string {
+	if provider := strings.ToLower(config.Get("map_provider")); provider != "" {
+		return provider
+	}
+	if runtime.GOOS == "darwin" {
+		return "apple"
+	}
+	return "osm"
+}
+
+// mapURL builds a link that shows lat/lon on the configured maps service.
+func mapURL(lat, lon float64, label string) (string, error) {
+	coords := fmt.Sprintf("%.6f,%.6f", lat, lon)
+
+	switch mapProvider() {
+	case "osm", "openstreetmap":
+		return fmt.Sprintf("https://www.openstreetmap.org/?mlat=%.6f&mlon=%.6f#map=14/%.6f/%.6f", lat, lon, lat, lon), nil
+	case "google":
+		return "https://www.google.com/maps/search/?api=1&query=" + url.QueryEscape(coords), nil
+	case "apple":
+		params := url.Values{}
+		params.Add("ll", coords)
+		if label != "" {
+			params.Add("q", label)
+		}
+		return "https://maps.apple.com/?" + params.Encode(), nil
+	default:
+		return "", fmt.Errorf("unknown map provider '%s' (use osm, google or apple)", config.Get("map_provider"))
+	}
+}
+
+// openMap shows lat/lon in the browser using the configured maps service.
+func openMap(lat, lon float64, label string) error {
+	link, err := mapURL(lat, lon, label)
+	if err != nil {
+		return err
+	}
+
+	printInfo("Opening %s on the map...\n", label)
+	if err := OpenBrowser(link); err != nil {
+		return fmt.Errorf("failed to open browser: %v (URL: %s)", err, link)
+	}
+	return nil
+}
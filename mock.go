@@ -0,0 +1,576 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mockData holds the canned provider responses served with --mock.
+//
+//go:embed mockdata/*.json
+var mockData embed.FS
+
+// mockTransport answers every API request from the bundled fixtures, so all
+// commands work offline with deterministic output for demos and
+// screenshots.
+type mockTransport struct{}
+
+func (mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Health checks only need to see every provider answering
+	if req.Method == http.MethodHead {
+		return fixtureResponse(req, http.StatusOK, "text/html", ""), nil
+	}
+
+	var body string
+	var err error
+
+	switch req.URL.Host {
+	case "api.exchangerate-api.com":
+		body, err = mockRates(strings.TrimPrefix(req.URL.Path, "/v4/latest/"))
+	case "wttr.in":
+		body, err = mockWeather(strings.TrimPrefix(req.URL.Path, "/"))
+	case "query1.finance.yahoo.com":
+		body, err = mockStockChart(strings.TrimPrefix(req.URL.Path, "/v8/finance/chart/"))
+		if err != nil {
+			return fixtureResponse(req, http.StatusNotFound, "application/json", `{"chart":{"result":null}}`), nil
+		}
+	case "api.coingecko.com":
+		body, err = mockCryptoPrices(req.URL.Query().Get("ids"))
+	case "api.frankfurter.app":
+		if req.URL.Path == "/latest" {
+			body, err = mockRates(req.URL.Query().Get("from"))
+		} else {
+			body, err = mockTimeseries(req.URL)
+		}
+	case "openexchangerates.org":
+		body, err = mockRates("USD")
+	case "data.fixer.io":
+		body, err = mockFixerRates()
+	case "api.wise.com":
+		body, err = mockWiseComparison(req.URL.Query())
+	case "www.visa.co.uk":
+		body, err = mockCardRate(req.URL.Query().Get("fromCurr"), req.URL.Query().Get("toCurr"), 0.9982, func(rate float64) interface{} {
+			return map[string]interface{}{"originalValues": map[string]string{"fxRateVisa": fmt.Sprintf("%.8f", rate)}}
+		})
+	case "www.mastercard.us":
+		body, err = mockCardRate(req.URL.Query().Get("crdhldBillCurr"), req.URL.Query().Get("transCurr"), 0.9986, func(rate float64) interface{} {
+			return map[string]interface{}{"data": map[string]float64{"conversionRate": rate}}
+		})
+	case "api.worldbank.org":
+		body, err = mockPPPFactor(strings.Split(req.URL.Path, "/"))
+	case "api.openweathermap.org":
+		body, err = mockOpenWeatherMap(req.URL.Query())
+	case "api.open-meteo.com":
+		switch {
+		case req.URL.Query().Has("current"):
+			body, err = mockOpenMeteoCurrent()
+		case req.URL.Query().Has("hourly"):
+			body, err = mockSnowForecast()
+		case req.URL.Query().Has("forecast_days"):
+			body, err = mockWeekForecast(req.URL.Query())
+		default:
+			body, err = mockDayForecast(req.URL.Query().Get("start_date"))
+		}
+	case "archive-api.open-meteo.com":
+		body, err = mockClimateHistory(req.URL.Query())
+	case "ipwho.is":
+		body, err = mockIPLocation()
+	case "nominatim.openstreetmap.org":
+		body, err = mockGeocode(req.URL.Query().Get("q"))
+	default:
+		err = fmt.Errorf("no mock response for %s", req.URL.Host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logVerbose("mock %s %s", req.Method, sanitizeURL(req.URL))
+	return fixtureResponse(req, http.StatusOK, "application/json", body), nil
+}
+
+// mockRates rebases the bundled USD rates onto base.
+func mockRates(base string) (string, error) {
+	data, err := mockData.ReadFile("mockdata/rates-usd.json")
+	if err != nil {
+		return "", err
+	}
+
+	var rates ExchangeRateResponse
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return "", fmt.Errorf("invalid mock rates: %v", err)
+	}
+
+	rebased, err := rebaseRates(&rates, base)
+	if err != nil {
+		return "", fmt.Errorf("no mock rates for %s", strings.ToUpper(base))
+	}
+
+	out, err := json.Marshal(rebased)
+	return string(out), err
+}
+
+// mockFixerRates wraps the EUR rates in fixer.io's response envelope.
+func mockFixerRates() (string, error) {
+	data, err := mockRates("EUR")
+	if err != nil {
+		return "", err
+	}
+	var rates map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &rates); err != nil {
+		return "", err
+	}
+	rates["success"] = true
+
+	out, err := json.Marshal(rates)
+	return string(out), err
+}
+
+// mockMidRate returns the bundled mid-market rate of from/to.
+func mockMidRate(from, to string) (float64, error) {
+	data, err := mockRates(from)
+	if err != nil {
+		return 0, err
+	}
+	var rates ExchangeRateResponse
+	if err := json.Unmarshal([]byte(data), &rates); err != nil {
+		return 0, err
+	}
+	rate, ok := rates.Rates[strings.ToUpper(to)]
+	if !ok {
+		return 0, fmt.Errorf("no mock rate for %s/%s", from, to)
+	}
+	return rate, nil
+}
+
+// mockWiseComparison quotes the mid-market rate with a fee of about 0.6%.
+func mockWiseComparison(query url.Values) (string, error) {
+	rate, err := mockMidRate(query.Get("sourceCurrency"), query.Get("targetCurrency"))
+	if err != nil {
+		return "", err
+	}
+	amount, _ := strconv.ParseFloat(query.Get("sendAmount"), 64)
+
+	out, err := json.Marshal(map[string]interface{}{
+		"providers": []interface{}{map[string]interface{}{
+			"alias":  "wise",
+			"quotes": []interface{}{map[string]float64{"rate": rate, "fee": math.Round(amount*0.0057*100) / 100}},
+		}},
+	})
+	return string(out), err
+}
+
+// mockCardRate marks the mid-market rate down by markup and wraps the
+// cardholder's currency per unit spent in a card network's envelope.
+func mockCardRate(billing, spent string, markup float64, envelope func(rate float64) interface{}) (string, error) {
+	rate, err := mockMidRate(billing, spent)
+	if err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(envelope(1 / (rate * markup)))
+	return string(out), err
+}
+
+// mockWeather returns the bundled forecast, relabelled with the requested
+// location so demos read naturally.
+func mockWeather(query string) (string, error) {
+	data, err := mockData.ReadFile("mockdata/weather.json")
+	if err != nil {
+		return "", err
+	}
+	if query == "" {
+		return string(data), nil
+	}
+
+	var weather map[string]interface{}
+	if err := json.Unmarshal(data, &weather); err != nil {
+		return "", fmt.Errorf("invalid mock weather: %v", err)
+	}
+
+	name, country := query, ""
+	if unescaped, err := url.QueryUnescape(query); err == nil {
+		name = unescaped
+	}
+	lat, lon := "18.788", "98.985"
+	if city := findCity(name); city != nil {
+		name, country = city.Name, city.Country
+		lat, lon = fmt.Sprintf("%.3f", city.Lat), fmt.Sprintf("%.3f", city.Lon)
+	}
+	// Like wttr.in, answer a point with the nearest town
+	if pointLat, pointLon, ok := parseCoordinates(name); ok {
+		nearest := &cities[0]
+		for i := range cities {
+			if distanceKm(pointLat, pointLon, cities[i].Lat, cities[i].Lon) < distanceKm(pointLat, pointLon, nearest.Lat, nearest.Lon) {
+				nearest = &cities[i]
+			}
+		}
+		name, country = nearest.Name, nearest.Country
+		lat, lon = fmt.Sprintf("%.3f", nearest.Lat), fmt.Sprintf("%.3f", nearest.Lon)
+	}
+	weather["nearest_area"] = []interface{}{map[string]interface{}{
+		"areaName":  []interface{}{map[string]interface{}{"value": name}},
+		"country":   []interface{}{map[string]interface{}{"value": country}},
+		"latitude":  lat,
+		"longitude": lon,
+	}}
+
+	out, err := json.Marshal(weather)
+	return string(out), err
+}
+
+// mockCoinPrices are the canned USD prices of each coin.
+var mockCoinPrices = map[string]float64{
+	"bitcoin":     94250,
+	"ethereum":    3310,
+	"tether":      1,
+	"usd-coin":    1,
+	"solana":      187.4,
+	"binancecoin": 701.2,
+	"ripple":      2.61,
+	"cardano":     0.98,
+	"dogecoin":    0.36,
+	"litecoin":    104.3,
+	"polkadot":    6.72,
+	"tron":        0.24,
+	"monero":      198.5,
+}
+
+// mockCryptoPrices answers CoinGecko's simple price endpoint.
+func mockCryptoPrices(ids string) (string, error) {
+	prices := make(map[string]map[string]float64)
+	for _, id := range strings.Split(ids, ",") {
+		if price, ok := mockCoinPrices[id]; ok {
+			// A small move either way, the same on every run
+			prices[id] = map[string]float64{"usd": price, "usd_24h_change": float64(len(id)%5)*0.9 - 1.6}
+		}
+	}
+	out, err := json.Marshal(prices)
+	return string(out), err
+}
+
+// mockStocks are the canned price and previous close of a few stocks.
+var mockStocks = map[string][2]float64{
+	"AAPL":  {228.52, 226.10},
+	"AMZN":  {186.40, 188.95},
+	"GOOGL": {164.74, 163.20},
+	"MSFT":  {417.35, 414.82},
+	"NVDA":  {135.29, 138.07},
+	"TSLA":  {248.50, 241.05},
+}
+
+// mockStockChart answers Yahoo Finance's chart endpoint for the canned
+// stocks.
+func mockStockChart(symbol string) (string, error) {
+	prices, ok := mockStocks[strings.ToUpper(symbol)]
+	if !ok {
+		return "", fmt.Errorf("no mock quote for %s", symbol)
+	}
+	meta := map[string]interface{}{"currency": "USD", "regularMarketPrice": prices[0], "chartPreviousClose": prices[1]}
+	out, err := json.Marshal(map[string]interface{}{
+		"chart": map[string]interface{}{"result": []interface{}{map[string]interface{}{"meta": meta}}},
+	})
+	return string(out), err
+}
+
+// mockPPPFactors are rounded 2023 PPP conversion factors for a few
+// economies.
+var mockPPPFactors = map[string]float64{
+	"USA": 1, "EMU": 0.69, "GBR": 0.67, "JPN": 94.5, "AUS": 1.45,
+	"CAN": 1.21, "CHE": 1.05, "THA": 11.9, "VNM": 7670, "IDN": 4760,
+	"MYS": 1.62, "PHL": 19.6, "SGP": 0.83, "MEX": 10.1, "BRA": 2.48,
+	"IND": 22.4, "TUR": 8.9, "ZAF": 7.2, "KOR": 840,
+}
+
+// mockPPPFactor answers the World Bank indicator endpoint, whose path is
+// /v2/country/<economy>/indicator/<id>.
+func mockPPPFactor(path []string) (string, error) {
+	if len(path) < 4 {
+		return "", fmt.Errorf("unexpected World Bank path")
+	}
+	economy := strings.ToUpper(path[3])
+	observations := []interface{}{}
+	if value, ok := mockPPPFactors[economy]; ok {
+		observations = append(observations, map[string]interface{}{"date": "2023", "value": value})
+	}
+	out, err := json.Marshal([]interface{}{map[string]int{"page": 1, "pages": 1}, observations})
+	return string(out), err
+}
+
+// mockOpenWeatherMap answers with the bundled wttr.in conditions in
+// OpenWeatherMap's shape.
+func mockOpenWeatherMap(query url.Values) (string, error) {
+	place := query.Get("q")
+	if query.Has("lat") {
+		place = query.Get("lat") + "," + query.Get("lon")
+	}
+	data, err := mockWeather(url.PathEscape(place))
+	if err != nil {
+		return "", err
+	}
+	var forecast WttrResponse
+	if err := json.Unmarshal([]byte(data), &forecast); err != nil {
+		return "", err
+	}
+	weather, err := wttrProvider{}.currentFrom(&forecast, "")
+	if err != nil {
+		return "", err
+	}
+
+	var response WeatherResponse
+	response.Coord.Lat, response.Coord.Lon = weather.Lat, weather.Lon
+	response.Main.Temp, response.Main.FeelsLike = weather.TempC, weather.FeelsLikeC
+	response.Main.Humidity, response.Main.Pressure = weather.Humidity, int(weather.PressureHPa)
+	response.Weather = append(response.Weather, struct {
+		Description string `json:"description"`
+		Main        string `json:"main"`
+	}{strings.ToLower(weather.Condition), "Clouds"})
+	response.Wind.Speed = math.Round(weather.WindKmh/3.6*10) / 10
+	response.Wind.Deg = 135
+	visibility := int(weather.VisibilityKm * 1000)
+	response.Visibility = &visibility
+	response.Clouds.All = weather.CloudCover
+	response.Name, _, _ = strings.Cut(weather.Location, ",")
+	response.Name = strings.TrimSpace(response.Name)
+	if city := findCity(response.Name); city != nil {
+		response.Sys.Country = city.Country
+	}
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	response.Timezone = 7 * 3600
+	response.Sys.Sunrise = day.Add(6*time.Hour + 58*time.Minute - 7*time.Hour).Unix()
+	response.Sys.Sunset = day.Add(18*time.Hour + 11*time.Minute - 7*time.Hour).Unix()
+
+	out, err := json.Marshal(response)
+	return string(out), err
+}
+
+// mockOpenMeteoCurrent returns the bundled conditions in Open-Meteo's
+// shape.
+func mockOpenMeteoCurrent() (string, error) {
+	var response openMeteoCurrent
+	tempC, feelsLikeC, humidity, wind, direction, pressure, cloud, visibility, uv := 31.2, 34.0, 62.0, 11.0, 135.0, 1010.0, 25.0, 10000.0, 8.0
+	code := 2
+	current := &response.Current
+	current.TempC, current.FeelsLikeC, current.Humidity = &tempC, &feelsLikeC, &humidity
+	current.WeatherCode, current.WindKmh, current.WindDirection = &code, &wind, &direction
+	current.PressureHPa, current.CloudCover, current.Visibility = &pressure, &cloud, &visibility
+	day := time.Now().Format("2006-01-02")
+	response.Daily.Sunrise = []string{day + "T06:58"}
+	response.Daily.Sunset = []string{day + "T18:11"}
+	response.Daily.UVIndex = []*float64{&uv}
+
+	out, err := json.Marshal(response)
+	return string(out), err
+}
+
+// mockDayForecast returns a mild, showery day.
+func mockDayForecast(day string) (string, error) {
+	var response openMeteoDaily
+	code, maxC, minC, precip, chance, wind, uv := 80, 19.4, 12.1, 2.3, 55.0, 18.0, 4.5
+	response.Daily.Time = []string{day}
+	response.Daily.WindKmh = []*float64{&wind}
+	response.Daily.UVIndex = []*float64{&uv}
+	response.Daily.WeatherCode = []*int{&code}
+	response.Daily.MaxC = []*float64{&maxC}
+	response.Daily.MinC = []*float64{&minC}
+	response.Daily.PrecipMM = []*float64{&precip}
+	response.Daily.RainChance = []*float64{&chance}
+
+	out, err := json.Marshal(response)
+	return string(out), err
+}
+
+// mockWeekForecast returns a week that is warmer, sunnier and drier the
+// nearer the place is to the tropics, so saved places rank differently.
+func mockWeekForecast(query url.Values) (string, error) {
+	lat, err := strconv.ParseFloat(query.Get("latitude"), 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid mock latitude")
+	}
+	days, err := strconv.Atoi(query.Get("forecast_days"))
+	if err != nil {
+		return "", fmt.Errorf("invalid mock forecast_days")
+	}
+
+	warmth := math.Cos(math.Abs(lat) * math.Pi / 180)
+	var response openMeteoDaily
+	today := time.Now().UTC()
+	for i := 0; i < days; i++ {
+		code := 1
+		maxC := math.Round((12+20*warmth+float64(i%3))*10) / 10
+		minC := maxC - 8
+		sun := (4 + 6*warmth) * 3600
+		precip := 0.0
+		if i%(2+int(warmth*4)) == 0 {
+			code, precip, sun = 61, math.Round((8-5*warmth)*10)/10, sun/2
+		}
+		chance := math.Min(precip*10, 100)
+		response.Daily.Time = append(response.Daily.Time, today.AddDate(0, 0, i).Format("2006-01-02"))
+		response.Daily.WeatherCode = append(response.Daily.WeatherCode, &code)
+		response.Daily.MaxC = append(response.Daily.MaxC, &maxC)
+		response.Daily.MinC = append(response.Daily.MinC, &minC)
+		response.Daily.PrecipMM = append(response.Daily.PrecipMM, &precip)
+		response.Daily.RainChance = append(response.Daily.RainChance, &chance)
+		response.Daily.SunSeconds = append(response.Daily.SunSeconds, &sun)
+		wind, uv := 12+float64(i%4)*5, math.Round(3+8*warmth)
+		response.Daily.WindKmh = append(response.Daily.WindKmh, &wind)
+		response.Daily.UVIndex = append(response.Daily.UVIndex, &uv)
+	}
+
+	out, err := json.Marshal(response)
+	return string(out), err
+}
+
+// mockClimateHistory returns years of daily weather following the seasons
+// of the northern hemisphere, with rain every other day in summer and every
+// fifth day otherwise.
+func mockClimateHistory(query url.Values) (string, error) {
+	start, err1 := time.Parse("2006-01-02", query.Get("start_date"))
+	end, err2 := time.Parse("2006-01-02", query.Get("end_date"))
+	if err1 != nil || err2 != nil {
+		return "", fmt.Errorf("invalid mock date range")
+	}
+
+	var response openMeteoDaily
+	for day, i := start, 0; !day.After(end); day, i = day.AddDate(0, 0, 1), i+1 {
+		season := math.Cos(float64(day.YearDay()-200) / 365 * 2 * math.Pi)
+		maxC := math.Round((18+8*season)*10) / 10
+		minC := math.Round((11+6*season)*10) / 10
+		precip := 0.0
+		if (season > 0.3 && i%2 == 0) || i%5 == 0 {
+			precip = 4.2
+		}
+		response.Daily.Time = append(response.Daily.Time, day.Format("2006-01-02"))
+		response.Daily.MaxC = append(response.Daily.MaxC, &maxC)
+		response.Daily.MinC = append(response.Daily.MinC, &minC)
+		response.Daily.PrecipMM = append(response.Daily.PrecipMM, &precip)
+	}
+
+	out, err := json.Marshal(response)
+	return string(out), err
+}
+
+// mockSnowForecast returns a day of snow behind and three days ahead, in
+// UTC, with a powder day tomorrow.
+func mockSnowForecast() (string, error) {
+	var forecast SnowForecast
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	for hour := 0; hour < 4*24; hour++ {
+		at := start.Add(time.Duration(hour) * time.Hour)
+		depth := 1.8 + float64(hour)*0.004
+		snowfall := 0.4 + 0.4*math.Sin(float64(hour)/5)
+		if hour/24 == 2 {
+			snowfall *= 2.5
+		}
+		level := 650 + 150*math.Sin(float64(hour)/8)
+		forecast.Hourly.Time = append(forecast.Hourly.Time, at.Format("2006-01-02T15:00"))
+		forecast.Hourly.SnowDepth = append(forecast.Hourly.SnowDepth, &depth)
+		forecast.Hourly.Snowfall = append(forecast.Hourly.Snowfall, &snowfall)
+		forecast.Hourly.FreezingLevel = append(forecast.Hourly.FreezingLevel, &level)
+	}
+	for day := 0; day < 4; day++ {
+		var sum float64
+		for _, snowfall := range forecast.Hourly.Snowfall[day*24 : (day+1)*24] {
+			sum += *snowfall
+		}
+		sum = math.Round(sum*10) / 10
+		forecast.Daily.Time = append(forecast.Daily.Time, start.AddDate(0, 0, day).Format("2006-01-02"))
+		forecast.Daily.Snowfall = append(forecast.Daily.Snowfall, &sum)
+	}
+
+	out, err := json.Marshal(forecast)
+	return string(out), err
+}
+
+// mockTimeseries returns weekday rates wobbling gently around the bundled
+// rate, so trend charts have something to show.
+func mockTimeseries(u *url.URL) (string, error) {
+	start, end, ok := strings.Cut(strings.TrimPrefix(u.Path, "/"), "..")
+	if !ok {
+		return "", fmt.Errorf("no mock response for %s", u.Path)
+	}
+	from, to := u.Query().Get("from"), u.Query().Get("to")
+
+	data, err := mockRates(from)
+	if err != nil {
+		return "", err
+	}
+	var latest ExchangeRateResponse
+	if err := json.Unmarshal([]byte(data), &latest); err != nil {
+		return "", err
+	}
+	rate, ok := latest.Rates[to]
+	if !ok {
+		return "", fmt.Errorf("no mock rates for %s", to)
+	}
+
+	startDate, err1 := time.Parse("2006-01-02", start)
+	endDate, err2 := time.Parse("2006-01-02", end)
+	if err1 != nil || err2 != nil {
+		return "", fmt.Errorf("invalid mock date range %s", u.Path)
+	}
+
+	series := TimeseriesResponse{Base: from, StartDate: start, EndDate: end, Rates: make(map[string]map[string]float64)}
+	for day, i := startDate, 0; !day.After(endDate); day, i = day.AddDate(0, 0, 1), i+1 {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		wobble := 1 + 0.015*math.Sin(float64(i)/4) + 0.0004*float64(i)
+		series.Rates[day.Format("2006-01-02")] = map[string]float64{to: rate * wobble}
+	}
+
+	out, err := json.Marshal(series)
+	return string(out), err
+}
+
+// mockGeocode places built-in cities at their real coordinates and anything
+// else in Lisbon.
+func mockGeocode(query string) (string, error) {
+	result := NominatimResponse{Lat: "38.7223", Lon: "-9.1393", DisplayName: query + ", Portugal"}
+	if city := findCity(strings.TrimSpace(strings.Split(query, ",")[0])); city != nil {
+		result.Lat = fmt.Sprintf("%.4f", city.Lat)
+		result.Lon = fmt.Sprintf("%.4f", city.Lon)
+		result.DisplayName = city.Label()
+	}
+
+	out, err := json.Marshal([]NominatimResponse{result})
+	return string(out), err
+}
+
+// mockIPLocation places the caller in Chiang Mai, like the mock weather.
+func mockIPLocation() (string, error) {
+	out, err := json.Marshal(map[string]interface{}{
+		"success": true, "city": "Chiang Mai", "country": "Thailand", "latitude": 18.7883, "longitude": 98.9853,
+	})
+	return string(out), err
+}
+
+// mockSpeedTest returns a typical coworking-space connection.
+func mockSpeedTest() (*SpeedTestResult, *NetworkQuality) {
+	result := &SpeedTestResult{
+		Latency:       18 * time.Millisecond,
+		Jitter:        3 * time.Millisecond,
+		DownloadSpeed: 94.6,
+		UploadSpeed:   41.2,
+		ServerName:    "Chiang Mai",
+		ServerCountry: "Thailand",
+	}
+	return result, calculateNetworkQuality(result)
+}
+
+// mockPingLatencies are the canned round trip times for each ping server.
+var mockPingLatencies = map[string]time.Duration{
+	"Google DNS":     12 * time.Millisecond,
+	"Cloudflare DNS": 9 * time.Millisecond,
+	"Facebook":       24 * time.Millisecond,
+	"Sydney":         142 * time.Millisecond,
+	"London":         198 * time.Millisecond,
+	"New York":       231 * time.Millisecond,
+	"Los Angeles":    176 * time.Millisecond,
+	"Singapore":      38 * time.Millisecond,
+}
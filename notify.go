@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// notifyCommand returns the command that shows a desktop notification on
+// the current platform.
+func notifyCommand(title, message string) (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return exec.Command("osascript", "-e", script), nil
+	case "windows":
+		script := "[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null; " +
+			"$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02); " +
+			"$text = $xml.GetElementsByTagName('text'); " +
+			"$text[0].AppendChild($xml.CreateTextNode($env:NOMAD_TITLE)) > $null; " +
+			"$text[1].AppendChild($xml.CreateTextNode($env:NOMAD_MESSAGE)) > $null; " +
+			"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('nomad').Show([Windows.UI.Notifications.ToastNotification]::new($xml))"
+		cmd := exec.Command("powershell", "-NoProfile", "-Command", script)
+		// Passing the text through the environment avoids quoting it
+		cmd.Env = append(cmd.Environ(), "NOMAD_TITLE="+title, "NOMAD_MESSAGE="+message)
+		return cmd, nil
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return nil, fmt.Errorf("no notification tool found (install libnotify's notify-send)")
+		}
+		return exec.Command("notify-send", "--app-name=nomad", title, message), nil
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Notify shows a desktop notification.
+func Notify(title, message string) error {
+	cmd, err := notifyCommand(title, message)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("desktop notification failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// globalOptions holds settings that apply to every command. They default to
+// the config file and can be overridden by flags placed anywhere on the
+// command line.
+type globalOptions struct {
+	IPFamily string // "4", "6" or "" to use either address family
+	HTTP3    bool
+	Verbose  bool
+	// DoH enables DNS-over-HTTPS resolution through DoHProvider
+	DoH         bool
+	DoHProvider dohProvider
+	// RecordDir, when set, receives a copy of every API response
+	RecordDir string
+	// Plain drops icons, colors and spinners and leads with the main result,
+	// for screen readers; Speak reads the main result aloud
+	Plain bool
+	Speak bool
+	// Mock serves every command from bundled canned responses, offline
+	Mock bool
+	// NDJSON replaces the human output of streaming commands with one JSON
+	// event per line
+	NDJSON bool
+	// JSON is set by commands printing a single JSON document, which must
+	// be all that reaches stdout
+	JSON bool
+	// NoPager prints long output straight to the terminal
+	NoPager bool
+	// RateProvider names the exchange rate source, see rateProviders
+	RateProvider string
+	// Sources lists where each part of a result came from
+	Sources bool
+	// WeatherProvider names the weather source, see weatherProviders
+	WeatherProvider string
+	// Units is "metric" or "imperial" for temperatures, speeds and
+	// distances
+	Units string
+}
+
+var options globalOptions
+
+// parseGlobalOptions fills options from the config file and global flags,
+// returning the remaining arguments.
+func parseGlobalOptions(args []string) []string {
+	options.IPFamily = config.Get("ip_family")
+	options.HTTP3 = config.Bool("http3")
+	options.Verbose = config.Bool("verbose")
+	options.DoH = config.Bool("doh")
+	dohName := config.Get("doh_provider")
+	options.Plain = config.Bool("plain")
+	options.Speak = config.Bool("speak")
+	options.Mock, _ = strconv.ParseBool(os.Getenv("NOMAD_MOCK"))
+	options.RateProvider = config.Get("rate_provider")
+	options.Sources = config.Bool("sources")
+	options.Units = config.Get("units")
+	options.WeatherProvider = config.Get("weather_provider")
+
+	args, ipv4 := popFlag(args, "--ipv4")
+	args, ipv6 := popFlag(args, "--ipv6")
+	args, http3 := popFlag(args, "--http3")
+	args, verbose := popFlag(args, "--verbose")
+	args, doh := popFlag(args, "--doh")
+	args, dohFlagName, dohNameSet := popFlagValue(args, "--doh-provider")
+	args, options.RecordDir, _ = popFlagValue(args, "--record")
+	args, plain := popFlag(args, "--plain")
+	args, speak := popFlag(args, "--speak")
+	args, mock := popFlag(args, "--mock")
+	args, rateProvider, rateProviderSet := popFlagValue(args, "--provider")
+	args, options.NoPager = popFlag(args, "--no-pager")
+	args, options.NDJSON = popFlag(args, "--ndjson")
+	args, sources := popFlag(args, "--sources")
+	args, units, unitsSet := popFlagValue(args, "--units")
+	args, weatherProvider, weatherProviderSet := popFlagValue(args, "--weather-provider")
+
+	switch {
+	case ipv4 && ipv6:
+		printError("Error: --ipv4 and --ipv6 cannot be used together\n")
+		os.Exit(1)
+	case ipv4:
+		options.IPFamily = "4"
+	case ipv6:
+		options.IPFamily = "6"
+	}
+	if options.IPFamily != "" && options.IPFamily != "4" && options.IPFamily != "6" {
+		printWarning("Warning: ignoring ip_family '%s' in config (expected 4 or 6)\n", options.IPFamily)
+		options.IPFamily = ""
+	}
+
+	options.HTTP3 = options.HTTP3 || http3
+	options.Verbose = options.Verbose || verbose
+	options.Plain = options.Plain || plain
+	options.Speak = options.Speak || speak
+	options.Mock = options.Mock || mock
+	options.Sources = options.Sources || sources
+	if unitsSet {
+		options.Units = units
+	}
+	switch options.Units = strings.ToLower(options.Units); options.Units {
+	case "":
+		options.Units = unitsMetric
+	case "us":
+		options.Units = unitsImperial
+	case unitsMetric, unitsImperial:
+	default:
+		printError("Error: Unknown units '%s' (use metric or imperial)\n", options.Units)
+		os.Exit(1)
+	}
+	if rateProviderSet {
+		options.RateProvider = rateProvider
+	}
+	if weatherProviderSet {
+		options.WeatherProvider = weatherProvider
+	}
+	if options.Plain {
+		ansiEnabled = false
+	}
+
+	// Naming a provider on the command line implies --doh
+	if dohNameSet {
+		dohName = dohFlagName
+	}
+	options.DoH = options.DoH || doh || dohNameSet
+	if options.DoH {
+		provider, err := resolveDoHProvider(dohName)
+		if err != nil {
+			printError("Error: %v\n", err)
+			os.Exit(1)
+		}
+		options.DoHProvider = provider
+	}
+
+	return args
+}
+
+// logVerbose prints a diagnostic line to stderr when --verbose is set.
+func logVerbose(format string, args ...interface{}) {
+	if !options.Verbose {
+		return
+	}
+	clearLine()
+	fmt.Fprintf(os.Stderr, colorMagenta("[verbose] "+format+"\n"), args...)
+}
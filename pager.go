@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// pagerHelp is shown in the pager's status line.
+const pagerHelp = "space/b page · j/k line · g/G top/bottom · q quit"
+
+// Page writes text to stdout, opening the built-in pager when it is taller
+// than the terminal so long lists do not flood the scrollback. Pipes,
+// --plain and --no-pager get the text as is.
+func Page(text string) {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	height := terminalHeight() - 1
+	if options.NoPager || options.Plain || !isInteractive() || !ansiEnabled || len(lines) <= height {
+		fmt.Print(text)
+		return
+	}
+
+	if err := runPager(lines, height); err != nil {
+		// Fall back to plain output rather than losing it
+		fmt.Print(text)
+	}
+}
+
+// runPager shows lines a screen at a time on the alternate screen, with
+// less-like navigation keys.
+func runPager(lines []string, height int) error {
+	fd := int(os.Stdin.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %v", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	fmt.Print("\033[?1049h\033[?25l")
+	defer fmt.Print("\033[?25h\033[?1049l")
+
+	top := 0
+	last := len(lines) - height
+	buf := make([]byte, 16)
+	for {
+		renderPager(lines, top, height)
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil
+		}
+		key := string(buf[:n])
+
+		switch key {
+		case "q", "Q", "\x03", "\033": // Ctrl-C, Esc
+			return nil
+		case " ", "f", "\x06", "\033[6~": // Ctrl-F, Page Down
+			top += height
+		case "b", "\x02", "\033[5~": // Ctrl-B, Page Up
+			top -= height
+		case "j", "\r", "\n", "\033[B":
+			top++
+		case "k", "\033[A":
+			top--
+		case "d", "\x04":
+			top += height / 2
+		case "u", "\x15":
+			top -= height / 2
+		case "g", "<", "\033[H":
+			top = 0
+		case "G", ">", "\033[F":
+			top = last
+		}
+		top = max(0, min(top, last))
+	}
+}
+
+// renderPager redraws the visible lines and the status line.
+func renderPager(lines []string, top, height int) {
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	for _, line := range lines[top : top+height] {
+		b.WriteString(line + "\r\n")
+	}
+
+	bottom := top + height
+	status := fmt.Sprintf("lines %d-%d of %d", top+1, bottom, len(lines))
+	if bottom == len(lines) {
+		status += " (end)"
+	}
+	b.WriteString(colorCyan(status + " · " + pagerHelp))
+	fmt.Print(b.String())
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// pickerHeight is the maximum number of matches shown at once.
+const pickerHeight = 10
+
+var errPickCancelled = errors.New("selection cancelled")
+
+// isInteractive reports whether stdin and stdout are attached to a terminal,
+// i.e. whether it is safe to prompt the user.
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Pick opens an interactive fuzzy-search picker over items and returns the
+// selected entry. query pre-fills the search box.
+func Pick(prompt, query string, items []string) (string, error) {
+	if !isInteractive() || !ansiEnabled {
+		return "", fmt.Errorf("interactive selection requires a terminal")
+	}
+
+	fd := int(os.Stdin.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to enter raw mode: %v", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	selected := 0
+	matches := fuzzyFilter(query, items)
+	buf := make([]byte, 16)
+
+	for {
+		renderPicker(prompt, query, matches, selected)
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			clearPicker()
+			return "", fmt.Errorf("failed to read input: %v", err)
+		}
+		key := buf[:n]
+
+		switch {
+		case key[0] == 3 || (n == 1 && key[0] == 27): // Ctrl-C, Esc
+			clearPicker()
+			return "", errPickCancelled
+		case key[0] == '\r' || key[0] == '\n':
+			clearPicker()
+			if len(matches) == 0 {
+				return "", fmt.Errorf("no match for '%s'", query)
+			}
+			return matches[selected], nil
+		case string(key) == "\033[A" || key[0] == 16: // Up, Ctrl-P
+			if selected > 0 {
+				selected--
+			}
+		case string(key) == "\033[B" || key[0] == 14: // Down, Ctrl-N
+			if selected < len(matches)-1 && selected < pickerHeight-1 {
+				selected++
+			}
+		case key[0] == 127 || key[0] == 8: // Backspace
+			if query != "" {
+				_, size := utf8.DecodeLastRuneInString(query)
+				query = query[:len(query)-size]
+				matches, selected = fuzzyFilter(query, items), 0
+			}
+		case key[0] == 21: // Ctrl-U
+			query = ""
+			matches, selected = fuzzyFilter(query, items), 0
+		case key[0] >= 32 && key[0] != 127:
+			for _, r := range string(key) {
+				if unicode.IsPrint(r) {
+					query += string(r)
+				}
+			}
+			matches, selected = fuzzyFilter(query, items), 0
+		}
+	}
+}
+
+// renderPicker draws the prompt line followed by the visible matches, then
+// parks the cursor at the end of the query.
+func renderPicker(prompt, query string, matches []string, selected int) {
+	fmt.Print("\r\033[J")
+	fmt.Printf("%s %s", colorCyan(prompt+" ›"), query)
+
+	visible := matches
+	if len(visible) > pickerHeight {
+		visible = visible[:pickerHeight]
+	}
+	for i, match := range visible {
+		if i == selected {
+			fmt.Printf("\r\n%s", colorBold(colorCyan("▶ "+match)))
+		} else {
+			fmt.Printf("\r\n  %s", match)
+		}
+	}
+	if len(visible) == 0 {
+		fmt.Printf("\r\n  %s", colorYellow("No matches"))
+		visible = []string{""}
+	}
+
+	fmt.Printf("\033[%dA\r\033[%dC", len(visible), displayWidth(prompt+" › "+query))
+}
+
+// clearPicker erases the picker from the screen.
+func clearPicker() {
+	fmt.Print("\r\033[J")
+}
+
+// fuzzyFilter returns the items matching query, best matches first.
+func fuzzyFilter(query string, items []string) []string {
+	type scored struct {
+		item  string
+		score int
+	}
+
+	var results []scored
+	for _, item := range items {
+		if score, ok := fuzzyScore(query, item); ok {
+			results = append(results, scored{item, score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return len(results[i].item) < len(results[j].item)
+	})
+
+	matches := make([]string, len(results))
+	for i, r := range results {
+		matches[i] = r.item
+	}
+	return matches
+}
+
+// fuzzyScore reports whether every character of query appears in candidate
+// in order, and how good the match is. Consecutive characters and matches at
+// the start of a word score higher, so "chi" ranks "Chiang Mai" above
+// "Ho Chi Minh City".
+func fuzzyScore(query, candidate string) (int, bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return 0, true
+	}
+
+	q := []rune(query)
+	qi := 0
+	score := 0
+	lastMatch := -2
+	prev := ' '
+	for i, r := range []rune(strings.ToLower(candidate)) {
+		if qi < len(q) && r == q[qi] {
+			score++
+			if lastMatch == i-1 {
+				score += 5
+			}
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 8
+				if i == 0 {
+					score += 2
+				}
+			}
+			lastMatch = i
+			qi++
+		}
+		prev = r
+	}
+
+	return score, qi == len(q)
+}
+
+// pickCity lets the user choose a city from the offline dataset.
+func pickCity(query string) (string, error) {
+	return Pick("City", query, cityLabels())
+}
+
+// pickCurrency lets the user choose a currency and returns its ISO code.
+func pickCurrency(prompt, query string) (string, error) {
+	label, err := Pick(prompt, query, currencyLabels())
+	if err != nil {
+		return "", err
+	}
+	return currencyCodeFromLabel(label), nil
+}
+
+// pickCurrencyFrom lets the user choose between a few currencies, such as
+// the ones called "dollar", and returns the ISO code.
+func pickCurrencyFrom(prompt string, codes []string) (string, error) {
+	labels := make([]string, len(codes))
+	for i, code := range codes {
+		labels[i] = code
+		if c := findCurrency(code); c != nil {
+			labels[i] = c.Label()
+		}
+	}
+
+	label, err := Pick(prompt, "", labels)
+	if err != nil {
+		return "", err
+	}
+	return currencyCodeFromLabel(label), nil
+}
+
+// mustPick unwraps the result of a picker, exiting if the user cancelled or
+// the picker could not be shown.
+func mustPick(choice string, err error) string {
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return choice
+}
@@ -19,8 +19,9 @@ type PingResult struct {
 	Error   error
 }
 
-// RunPingTests pings a list of servers and returns the results.
-func RunPingTests() []PingResult {
+// RunPingTests pings a list of servers and returns the results. onResult,
+// if not nil, is called with each result as soon as it is known.
+func RunPingTests(onResult func(PingResult)) []PingResult {
 	servers := []Server{
 		{Name: "Google DNS", Address: "8.8.8.8"},
 		{Name: "Cloudflare DNS", Address: "1.1.1.1"},
@@ -34,7 +35,14 @@ func RunPingTests() []PingResult {
 
 	results := make([]PingResult, len(servers))
 	for i, server := range servers {
-		results[i] = pingServer(server)
+		if options.Mock {
+			results[i] = PingResult{Server: server, Latency: mockPingLatencies[server.Name]}
+		} else {
+			results[i] = pingServer(server)
+		}
+		if onResult != nil {
+			onResult(results[i])
+		}
 	}
 
 	return results
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const placesFile = "places.json"
+
+// reservedPlaces are words the location chain already understands, so they
+// can't be saved as place names.
+var reservedPlaces = map[string]bool{
+	"here": true,
+	"home": true,
+}
+
+// loadPlaces returns the saved places, keyed by lowercase name.
+func loadPlaces() map[string]string {
+	places := make(map[string]string)
+	if err := loadJSON(placesFile, &places); err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return places
+}
+
+func savePlaces(places map[string]string) {
+	if err := saveJSON(placesFile, places); err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// lookupPlace returns the location saved under name, ignoring case.
+func lookupPlace(name string) (string, bool) {
+	var places map[string]string
+	if err := loadJSON(placesFile, &places); err != nil {
+		logVerbose("Ignoring saved places: %v", err)
+		return "", false
+	}
+	query, ok := places[strings.ToLower(strings.TrimSpace(name))]
+	return query, ok
+}
+
+// handlePlaces implements `nomad places [add|rm]`.
+func handlePlaces(args []string) {
+	if len(args) == 0 || args[0] == "list" || args[0] == "ls" {
+		listPlaces()
+		return
+	}
+
+	switch args[0] {
+	case "add":
+		addPlace(args[1:])
+	case "rm", "remove":
+		removePlace(args[1:])
+	default:
+		printError("Unknown places command: %s\n", args[0])
+		printPlacesUsage()
+		os.Exit(1)
+	}
+}
+
+func printPlacesUsage() {
+	printInfo("Usage:\n")
+	fmt.Println("  nomad places                         List saved places")
+	fmt.Println("  nomad places add <name> <location>   Save a place to use by name in weather, time and focus")
+	fmt.Println("  nomad places add <name> --here       Save where you are now")
+	fmt.Println("  nomad places rm <name>")
+	printInfo("Example: nomad places add parents Leeds, UK\n")
+}
+
+func addPlace(args []string) {
+	args, here := popFlag(args, "--here")
+	if len(args) < 1 || (len(args) < 2 && !here) || (len(args) > 1 && here) {
+		printPlacesUsage()
+		os.Exit(1)
+	}
+
+	name := strings.ToLower(args[0])
+	if reservedPlaces[name] {
+		printError("Error: '%s' already means something to nomad; pick another name\n", name)
+		os.Exit(1)
+	}
+
+	query := strings.Join(args[1:], " ")
+	if here {
+		err := WithSpinner("Detecting location...", func() error {
+			var lookupErr error
+			query, lookupErr = hereQuery()
+			return lookupErr
+		})
+		if err != nil {
+			printError("Error: Could not detect your location: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	places := loadPlaces()
+	verb := "Saved"
+	if _, ok := places[name]; ok {
+		verb = "Updated"
+	}
+	places[name] = query
+	savePlaces(places)
+	printSuccess("%s %s: %s\n", verb, name, query)
+}
+
+func removePlace(args []string) {
+	if len(args) < 1 {
+		printPlacesUsage()
+		os.Exit(1)
+	}
+
+	places := loadPlaces()
+	name := strings.ToLower(args[0])
+	if _, ok := places[name]; !ok {
+		printError("Error: No place named '%s'\n", args[0])
+		os.Exit(1)
+	}
+	delete(places, name)
+	savePlaces(places)
+	printSuccess("Removed %s\n", name)
+}
+
+func listPlaces() {
+	places := loadPlaces()
+	homeCity := strings.TrimSpace(config.Get("home_city"))
+	if len(places) == 0 && homeCity == "" {
+		printInfo("No saved places yet.\n")
+		printInfo("Example: nomad places add parents Leeds, UK\n")
+		return
+	}
+
+	names := make([]string, 0, len(places))
+	for name := range places {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println()
+	printTitle("%s Places\n", iconLocation(""))
+
+	table := NewTable("Name", "Location").SetTruncate(1, 40)
+	if homeCity != "" {
+		table.AddRow("home", homeCity+" (home_city)")
+	}
+	for _, name := range names {
+		table.AddRow(name, places[name])
+	}
+	table.Print()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+// pppEconomies maps currencies to the World Bank economy whose PPP
+// conversion factor stands for them. The euro uses the euro area as a
+// whole.
+var pppEconomies = map[string]string{
+	"USD": "USA", "EUR": "EMU", "GBP": "GBR", "JPY": "JPN", "CNY": "CHN",
+	"INR": "IND", "AUD": "AUS", "CAD": "CAN", "CHF": "CHE", "NZD": "NZL",
+	"SEK": "SWE", "NOK": "NOR", "DKK": "DNK", "ISK": "ISL", "PLN": "POL",
+	"CZK": "CZE", "HUF": "HUN", "RON": "ROU", "BGN": "BGR", "RSD": "SRB",
+	"TRY": "TUR", "UAH": "UKR", "GEL": "GEO", "AMD": "ARM", "ZAR": "ZAF",
+	"EGP": "EGY", "MAD": "MAR", "KES": "KEN", "NGN": "NGA", "TZS": "TZA",
+	"AED": "ARE", "SAR": "SAU", "QAR": "QAT", "ILS": "ISR", "JOD": "JOR",
+	"THB": "THA", "VND": "VNM", "IDR": "IDN", "MYR": "MYS", "PHP": "PHL",
+	"SGD": "SGP", "KRW": "KOR", "HKD": "HKG", "LKR": "LKA", "NPR": "NPL",
+	"PKR": "PAK", "BDT": "BGD", "KHR": "KHM", "LAK": "LAO", "MNT": "MNG",
+	"MXN": "MEX", "BRL": "BRA", "ARS": "ARG", "CLP": "CHL", "COP": "COL",
+	"PEN": "PER", "CRC": "CRI", "UYU": "URY", "GTQ": "GTM", "DOP": "DOM",
+}
+
+// pppCacheTTL is how long PPP factors are reused. The World Bank
+// publishes them once a year.
+const pppCacheTTL = 7 * 24 * time.Hour
+
+// PPPFactor is an economy's PPP conversion factor: local currency units
+// per international dollar.
+type PPPFactor struct {
+	Value float64
+	Year  string
+}
+
+// handlePPP implements `nomad cv ppp <amount> <from> <to>`, showing what an
+// amount is worth in purchasing power rather than at the market rate.
+func handlePPP(args []string) {
+	if len(args) < 3 {
+		printError("Usage: nomad cv ppp <amount> <from_currency> <to_currency>\n")
+		printInfo("Example: nomad cv ppp 3000 usd vnd\n")
+		os.Exit(1)
+	}
+	amount, err := evalExpression(args[0])
+	if err != nil || amount <= 0 {
+		printError("Error: Invalid amount '%s'\n", args[0])
+		os.Exit(1)
+	}
+	from, to := mustResolveCurrency(args[1]), mustResolveCurrency(args[2])
+	for _, code := range []string{from, to} {
+		if _, ok := pppEconomies[code]; !ok {
+			printError("Error: No purchasing power data for %s\n", code)
+			os.Exit(1)
+		}
+	}
+
+	snapshotFallback = true
+	var rate float64
+	var fromPPP, toPPP PPPFactor
+	err = WithSpinner("Fetching purchasing power data...", func() error {
+		var wg sync.WaitGroup
+		var rateErr, fromErr, toErr error
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			rate, rateErr = getExchangeRate(from, to)
+		}()
+		go func() {
+			defer wg.Done()
+			fromPPP, fromErr = getPPPFactor(pppEconomies[from])
+		}()
+		go func() {
+			defer wg.Done()
+			toPPP, toErr = getPPPFactor(pppEconomies[to])
+		}()
+		wg.Wait()
+
+		switch {
+		case rateErr != nil:
+			return fmt.Errorf("failed to get exchange rate: %v", rateErr)
+		case fromErr != nil:
+			return fmt.Errorf("no purchasing power data for %s: %v", from, fromErr)
+		case toErr != nil:
+			return fmt.Errorf("no purchasing power data for %s: %v", to, toErr)
+		}
+		return nil
+	})
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// pppRate is how many units of to buy what one unit of from buys at home
+	pppRate := toPPP.Value / fromPPP.Value
+	priceLevel := pppRate / rate
+	market := amount * rate
+	sameLifestyle := amount * pppRate
+
+	announceResult("%s %s feels like %s %s locally", formatAmount(amount, from), from, formatAmount(sameLifestyle, to), to)
+
+	fmt.Println()
+	printTitle("%s %s %s in %s, adjusted for prices\n", iconCurrency(""), formatAmount(amount, from), from, to)
+	fmt.Printf("  %s %s %s %s\n", padRight(iconInfo("Market rate"), 14), colorGreen(formatAmount(market, to)), to,
+		colorCyan(fmt.Sprintf("(1 %s = %s %s)", from, formatGrouped(rate), to)))
+	fmt.Printf("  %s %s %s %s\n", padRight(iconSuccess("Feels like"), 14), colorGreen(formatAmount(sameLifestyle, to)), to,
+		colorCyan(fmt.Sprintf("(1 %s = %s %s at PPP, %s %s at the market rate)", from, formatGrouped(pppRate), to, formatAmount(sameLifestyle/rate, from), from)))
+	fmt.Printf("  %s %s %s\n", padRight(iconInfo("Stretches to"), 14), colorYellow(formatAmount(amount/priceLevel, from)+" "+from),
+		colorCyan("of spending at home"))
+
+	comparison := "cheaper"
+	if priceLevel > 1 {
+		comparison = "dearer"
+	}
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Price level"), 14),
+		fmt.Sprintf("%.0f%% of %s prices, so about %.0f%% %s", priceLevel*100, from, math.Abs(1-priceLevel)*100, comparison))
+
+	fmt.Println()
+	printInfo("PPP conversion factors from the World Bank (%s %s, %s %s); they average a whole economy, not a traveller's basket\n",
+		from, fromPPP.Year, to, toPPP.Year)
+	printSnapshotNotice()
+	addRateSource()
+	addSource(Source{What: "PPP factors", From: "World Bank", Confidence: confidenceMedium, Updated: min(fromPPP.Year, toPPP.Year)})
+	printSources()
+}
+
+// worldBankObservation is one yearly value of a World Bank indicator.
+type worldBankObservation struct {
+	Date  string   `json:"date"`
+	Value *float64 `json:"value"`
+}
+
+// getPPPFactor fetches the latest PPP conversion factor (GDP) for a World
+// Bank economy.
+func getPPPFactor(economy string) (PPPFactor, error) {
+	var observations []worldBankObservation
+	err := fetchCachedJSON("ppp:"+economy,
+		fmt.Sprintf("https://api.worldbank.org/v2/country/%s/indicator/PA.NUS.PPP?format=json&mrnev=1", economy), pppCacheTTL,
+		func(body []byte) error {
+			// The response is a [paging, observations] pair
+			var response []json.RawMessage
+			if err := json.Unmarshal(body, &response); err != nil || len(response) < 2 {
+				return schemaMismatch("api.worldbank.org", "expected a [paging, observations] pair", body)
+			}
+			if err := json.Unmarshal(response[1], &observations); err != nil {
+				return schemaMismatch("api.worldbank.org", "invalid observations: "+err.Error(), body)
+			}
+			return nil
+		})
+	if err != nil {
+		return PPPFactor{}, err
+	}
+	for _, observation := range observations {
+		if observation.Value != nil && *observation.Value > 0 {
+			return PPPFactor{Value: *observation.Value, Year: observation.Date}, nil
+		}
+	}
+	return PPPFactor{}, fmt.Errorf("no recent value")
+}
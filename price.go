@@ -0,0 +1,306 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// extraCurrencySymbols maps symbols and prefixes that are not in the
+// currency dataset, or that are shared by several currencies, to the code
+// they most commonly mean on booking and shopping sites.
+var extraCurrencySymbols = map[string]string{
+	"$":   "USD",
+	"US$": "USD",
+	"AU$": "AUD",
+	"CA$": "CAD",
+	"¥":   "JPY",
+	"JP¥": "JPY",
+	"CN¥": "CNY",
+	"RMB": "CNY",
+	"元":   "CNY",
+	"円":   "JPY",
+	"MX$": "MXN",
+	"€":   "EUR",
+	"£":   "GBP",
+}
+
+var (
+	priceNumberPattern = regexp.MustCompile(`[-+]?\d[\d.,' ]*`)
+	priceCodePattern   = regexp.MustCompile(`\b[A-Z]{3}\b`)
+)
+
+// parsePrice extracts an amount and currency from a price as it appears on
+// a website, e.g. "€1.299,00", "$1,299.00", "THB 2,400" or "1 299,00 zł".
+func parsePrice(text string) (float64, string, error) {
+	text = strings.Map(func(r rune) rune {
+		// Non-breaking and thin spaces are common thousands separators
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		return r
+	}, strings.TrimSpace(text))
+	if text == "" {
+		return 0, "", fmt.Errorf("no price found")
+	}
+
+	currency, rest, err := extractCurrency(text)
+	if err != nil {
+		return 0, "", err
+	}
+
+	number := strings.TrimSpace(priceNumberPattern.FindString(rest))
+	if number == "" {
+		return 0, "", fmt.Errorf("no amount found in '%s'", text)
+	}
+
+	amount, err := parseLocaleNumber(number)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid amount '%s'", number)
+	}
+
+	return amount, currency, nil
+}
+
+// extractCurrency finds an ISO code or currency symbol in text and returns
+// it together with the text that remains once it is removed.
+func extractCurrency(text string) (string, string, error) {
+	// An explicit ISO code is the least ambiguous, but only one written in
+	// capitals that nomad knows, so words like "Try" or "PAD" in a menu
+	// aren't taken for currencies
+	for _, match := range priceCodePattern.FindAllStringIndex(text, -1) {
+		code := text[match[0]:match[1]]
+		if findCurrency(code) != nil || isCrypto(code) {
+			return code, text[:match[0]] + " " + text[match[1]:], nil
+		}
+	}
+
+	// Otherwise look for symbols, longest first so "US$" wins over "$"
+	symbols := currencySymbols()
+	keys := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		keys = append(keys, symbol)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	for _, symbol := range keys {
+		index := indexSymbol(text, symbol)
+		if index < 0 {
+			continue
+		}
+
+		codes := symbols[symbol]
+		if len(codes) > 1 {
+			return "", "", fmt.Errorf("'%s' is used by %s; add the currency code to the price", symbol, strings.Join(codes, ", "))
+		}
+		return codes[0], text[:index] + " " + text[index+len(symbol):], nil
+	}
+
+	return "", "", fmt.Errorf("no currency found in '%s'", text)
+}
+
+// currencySymbols maps every known symbol to the currency codes using it.
+func currencySymbols() map[string][]string {
+	symbols := make(map[string][]string)
+	for _, c := range currencies {
+		if _, preferred := extraCurrencySymbols[c.Symbol]; preferred {
+			continue
+		}
+		symbols[c.Symbol] = append(symbols[c.Symbol], c.Code)
+	}
+	for symbol, code := range extraCurrencySymbols {
+		symbols[symbol] = []string{code}
+	}
+	return symbols
+}
+
+// indexSymbol finds symbol in text. Symbols made of letters (e.g. "R",
+// "RM", "lei") must stand alone so they do not match inside words.
+func indexSymbol(text, symbol string) int {
+	isWord := true
+	for _, r := range symbol {
+		if !unicode.IsLetter(r) {
+			isWord = false
+		}
+	}
+
+	offset := 0
+	for {
+		index := strings.Index(text[offset:], symbol)
+		if index < 0 {
+			return -1
+		}
+		index += offset
+		if !isWord || (isBoundary(text, index-1) && isBoundary(text, index+len(symbol))) {
+			return index
+		}
+		offset = index + len(symbol)
+	}
+}
+
+// isBoundary reports whether the byte at i is outside text or not a letter.
+func isBoundary(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return true
+	}
+	r := rune(text[i])
+	return r < 0x80 && !unicode.IsLetter(r)
+}
+
+// parseLocaleNumber parses numbers using either "," or "." as the decimal
+// separator. When both appear the last one is the decimal separator; a
+// single separator followed by exactly three digits is read as a thousands
+// separator ("1.299" is 1299, "12,50" is 12.5).
+func parseLocaleNumber(s string) (float64, error) {
+	s = strings.NewReplacer(" ", "", "'", "").Replace(s)
+
+	lastDot := strings.LastIndex(s, ".")
+	lastComma := strings.LastIndex(s, ",")
+
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastDot > lastComma {
+			s = strings.ReplaceAll(s, ",", "")
+		} else {
+			s = strings.ReplaceAll(s, ".", "")
+			s = strings.Replace(s, ",", ".", 1)
+		}
+	case lastDot >= 0 || lastComma >= 0:
+		sep := "."
+		last := lastDot
+		if lastComma >= 0 {
+			sep, last = ",", lastComma
+		}
+
+		if strings.Count(s, sep) > 1 || len(s)-last-1 == 3 {
+			s = strings.ReplaceAll(s, sep, "")
+		} else {
+			s = strings.Replace(s, sep, ".", 1)
+		}
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
+
+// pricePeriods maps billing periods to their length in nights.
+var pricePeriods = map[string]float64{
+	"night": 1,
+	"day":   1,
+	"week":  7,
+	"month": 30,
+}
+
+// handlePrice normalizes an accommodation price to nightly, weekly and
+// monthly figures in a common currency so listings can be compared.
+func handlePrice(args []string) {
+	args, period, _ := popFlagValue(args, "--per")
+	args, daysStr, _ := popFlagValue(args, "--days")
+	args, peopleStr, _ := popFlagValue(args, "--people")
+	args, toCurrency, _ := popFlagValue(args, "--to")
+
+	if len(args) < 2 {
+		printError("Usage: nomad price <amount> <currency> [--per night|week|month] [--days N] [--people N] [--to currency]\n")
+		printInfo("Example: nomad price 2400 thb --per night --days 30 --to usd\n")
+		os.Exit(1)
+	}
+
+	amount, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || amount < 0 {
+		printError("Error: Invalid amount '%s'\n", args[0])
+		os.Exit(1)
+	}
+	fromCurrency := strings.ToUpper(args[1])
+
+	switch period = strings.ToLower(period); period {
+	case "":
+		period = "night"
+	case "daily":
+		period = "day"
+	case "nightly", "weekly", "monthly":
+		period = strings.TrimSuffix(period, "ly")
+	}
+	nights, ok := pricePeriods[period]
+	if !ok {
+		printError("Error: Unknown period '%s' (use night, week or month)\n", period)
+		os.Exit(1)
+	}
+
+	days := 30
+	if daysStr != "" {
+		if days, err = strconv.Atoi(daysStr); err != nil || days < 1 {
+			printError("Error: Invalid number of days '%s'\n", daysStr)
+			os.Exit(1)
+		}
+	}
+
+	people := 1
+	if peopleStr != "" {
+		if people, err = strconv.Atoi(peopleStr); err != nil || people < 1 {
+			printError("Error: Invalid number of people '%s'\n", peopleStr)
+			os.Exit(1)
+		}
+	}
+
+	if toCurrency == "" {
+		toCurrency = config.Get("home_currency")
+	}
+	toCurrency = strings.ToUpper(toCurrency)
+	if toCurrency == "" {
+		toCurrency = fromCurrency
+	}
+
+	rate := 1.0
+	if toCurrency != fromCurrency {
+		snapshotFallback = true
+		err = WithSpinner("Fetching exchange rates...", func() error {
+			var fetchErr error
+			rate, fetchErr = getExchangeRate(fromCurrency, toCurrency)
+			return fetchErr
+		})
+		if err != nil {
+			printError("Error getting exchange rate: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	perNight := amount / nights * rate
+
+	fmt.Println()
+	printTitle("%s Price Comparison\n", iconCurrency(""))
+	fmt.Printf("  %.2f %s per %s", amount, fromCurrency, period)
+	if toCurrency != fromCurrency {
+		fmt.Printf(" · 1 %s = %.4f %s", fromCurrency, rate, toCurrency)
+	}
+	fmt.Println()
+	fmt.Println()
+
+	headers := []string{"", toCurrency}
+	if people > 1 {
+		headers = append(headers, fmt.Sprintf("Per person (%d)", people))
+	}
+	table := NewTable(headers...)
+	addPriceRow := func(label string, total float64) {
+		row := []string{label, colorGreen(fmt.Sprintf("%.2f", total))}
+		if people > 1 {
+			row = append(row, colorCyan(fmt.Sprintf("%.2f", total/float64(people))))
+		}
+		table.AddRow(row...)
+	}
+	addPriceRow("Per night", perNight)
+	addPriceRow("Per week", perNight*7)
+	addPriceRow("Per month (30 nights)", perNight*30)
+	if days != 30 {
+		addPriceRow(fmt.Sprintf("Your stay (%d nights)", days), perNight*float64(days))
+	}
+	table.Print()
+	printSnapshotNotice()
+}
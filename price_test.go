@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		in       string
+		amount   float64
+		currency string
+	}{
+		{"120 THB", 120, "THB"},
+		{"THB 1,299.50", 1299.50, "THB"},
+		{"€1.299,00", 1299, "EUR"},
+		{"0.05 BTC", 0.05, "BTC"},
+		{"Try the Pad Thai 120 THB", 120, "THB"},
+		{"The total is 45 EUR", 45, "EUR"},
+		{"Per night: 80 USD", 80, "USD"},
+		{"try 30 €", 30, "EUR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			amount, currency, err := parsePrice(tt.in)
+			if err != nil {
+				t.Fatalf("parsePrice(%q) failed: %v", tt.in, err)
+			}
+			if amount != tt.amount || currency != tt.currency {
+				t.Errorf("parsePrice(%q) = %g %s, want %g %s", tt.in, amount, currency, tt.amount, tt.currency)
+			}
+		})
+	}
+}
+
+func TestParsePriceRejectsWords(t *testing.T) {
+	// Words that look like codes are not currencies
+	for _, in := range []string{"Try the Pad Thai, 120", "The And Per 45", "try 30", "PAD 120"} {
+		if amount, currency, err := parsePrice(in); err == nil {
+			t.Errorf("parsePrice(%q) = %g %s, want no price found", in, amount, currency)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import "fmt"
+
+// How much to trust a part of a result, from how its data was obtained.
+const (
+	// Live from a provider
+	confidenceHigh = "high"
+	// A fallback provider, a derived value or a curated dataset
+	confidenceMedium = "medium"
+	// An estimate or an offline snapshot
+	confidenceLow = "low"
+)
+
+// Source describes where one part of a result came from.
+type Source struct {
+	// What names the part of the result, e.g. "Exchange rate"
+	What string
+	// From is the provider or dataset
+	From       string
+	Confidence string
+	// Updated is when the data was current, "" when unknown
+	Updated string
+	// Check says what to double-check against, for anything short of high
+	// confidence
+	Check string
+}
+
+// builtInData labels values from the datasets compiled into nomad.
+const builtInData = "nomad's built-in data"
+
+// resultSources collects the sources of the result being shown.
+var resultSources []Source
+
+// addSource records where part of the result came from, for printSources.
+func addSource(source Source) {
+	resultSources = append(resultSources, source)
+}
+
+// printSources ends a result with its provenance. With --sources every
+// source is listed with its confidence and date; otherwise only datasets
+// and estimates get a one-line note, so users know what to double-check.
+func printSources() {
+	if options.Sources {
+		if len(resultSources) == 0 {
+			return
+		}
+		fmt.Println()
+		printInfo("Sources:\n")
+		table := NewTable("Part", "From", "Confidence", "Updated")
+		for _, source := range resultSources {
+			updated := source.Updated
+			switch {
+			case updated != "":
+			case source.From == builtInData:
+				updated = "with this build"
+			case source.Confidence == confidenceHigh:
+				updated = "live"
+			default:
+				updated = "unknown"
+			}
+			table.AddRow(source.What, source.From, confidenceColor(source.Confidence)(source.Confidence), updated)
+		}
+		table.Print()
+		for _, source := range resultSources {
+			if source.Check != "" {
+				printWarning("  %s: %s\n", source.What, source.Check)
+			}
+		}
+		return
+	}
+
+	for _, source := range resultSources {
+		if source.Confidence == confidenceHigh || source.Check == "" {
+			continue
+		}
+		printWarning("  %s from %s (%s confidence); %s\n", source.What, source.From, source.Confidence, source.Check)
+	}
+}
+
+// confidenceColor colors a confidence level like a traffic light.
+func confidenceColor(confidence string) func(string) string {
+	switch confidence {
+	case confidenceHigh:
+		return colorGreen
+	case confidenceMedium:
+		return colorYellow
+	default:
+		return colorRed
+	}
+}
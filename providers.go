@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// providerRole is a kind of data nomad fetches and where it comes from.
+type providerRole struct {
+	Role     string
+	Host     string
+	Fallback string
+}
+
+// providerRoles lists every backend in the order they are shown by
+// `nomad providers`.
+func providerRoles() []providerRole {
+	// An in-memory cache is gone by the next run, so it is no fallback
+	cacheNote := func(ttl time.Duration) string {
+		backend := strings.ToLower(config.Get("cache"))
+		if backend == "" || backend == "memory" {
+			return ""
+		}
+		return fmt.Sprintf("%s cache (%s)", backend, formatTTL(ttl))
+	}
+
+	rates := providerRole{Role: "Exchange rates", Fallback: cacheNote(ratesCacheTTL)}
+	if chain, err := rateProviderChain(); err == nil {
+		rates.Host = chain[0].Host()
+		var fallbacks []string
+		for _, provider := range chain[1:] {
+			fallbacks = append(fallbacks, provider.Name())
+		}
+		if rates.Fallback != "" {
+			fallbacks = append(fallbacks, rates.Fallback)
+		}
+		rates.Fallback = strings.Join(fallbacks, ", ")
+	} else {
+		rates.Host = options.RateProvider
+	}
+
+	weather := providerRole{Role: "Weather", Host: options.WeatherProvider, Fallback: cacheNote(weatherCacheTTL)}
+	if chain, err := weatherProviderChain(); err == nil {
+		weather.Host = chain[0].Host()
+		var fallbacks []string
+		for _, provider := range chain[1:] {
+			fallbacks = append(fallbacks, provider.Name())
+		}
+		if weather.Fallback != "" {
+			fallbacks = append(fallbacks, weather.Fallback)
+		}
+		weather.Fallback = strings.Join(fallbacks, ", ")
+	}
+
+	return []providerRole{
+		rates,
+		{"Crypto prices", "api.coingecko.com", cacheNote(cryptoCacheTTL)},
+		{"Stock quotes", "query1.finance.yahoo.com", cacheNote(cryptoCacheTTL)},
+		{"Rate history", "api.frankfurter.app", cacheNote(trendCacheTTL)},
+		{"Wise quotes", "api.wise.com", cacheNote(quoteCacheTTL)},
+		{"Visa rates", "www.visa.co.uk", cacheNote(quoteCacheTTL)},
+		{"Mastercard rates", "www.mastercard.us", cacheNote(quoteCacheTTL)},
+		{"PPP factors", "api.worldbank.org", cacheNote(pppCacheTTL)},
+		weather,
+		{"Snow reports", "api.open-meteo.com", cacheNote(snowCacheTTL)},
+		{"Date forecasts", "api.open-meteo.com", cacheNote(dayForecastCacheTTL)},
+		{"Weather ranking", "api.open-meteo.com", cacheNote(dayForecastCacheTTL)},
+		{"Climate normals", "archive-api.open-meteo.com", cacheNote(climateCacheTTL)},
+		{"Geocoder", "nominatim.openstreetmap.org", "built-in city list"},
+		{"Speed test", "www.speedtest.net", ""},
+		{"Visa data", "www.emirates.com", "opened in the browser"},
+	}
+}
+
+// formatTTL shows a cache lifetime in its largest whole unit.
+func formatTTL(ttl time.Duration) string {
+	switch {
+	case ttl >= time.Hour && ttl%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(ttl.Hours()))
+	case ttl >= time.Minute && ttl%time.Minute == 0:
+		return fmt.Sprintf("%dm", int(ttl.Minutes()))
+	default:
+		return ttl.String()
+	}
+}
+
+// providerStatusFile records when each API host last answered successfully.
+const providerStatusFile = "providers.json"
+
+var (
+	providerStatusMu   sync.Mutex
+	providerStatusSeen = make(map[string]bool)
+)
+
+// statusTransport remembers the last successful response from each host,
+// writing it at most once per host and run.
+type statusTransport struct {
+	next http.RoundTripper
+}
+
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode < 400 {
+		recordProviderSuccess(req.URL.Host)
+	}
+	return resp, err
+}
+
+// recordProviderSuccess stores the current time as host's last success.
+// Failing to save is not worth interrupting a command for.
+func recordProviderSuccess(host string) {
+	providerStatusMu.Lock()
+	defer providerStatusMu.Unlock()
+
+	if providerStatusSeen[host] {
+		return
+	}
+	providerStatusSeen[host] = true
+
+	status := make(map[string]time.Time)
+	if err := loadJSON(providerStatusFile, &status); err != nil {
+		logVerbose("provider status: %v", err)
+		return
+	}
+	status[host] = time.Now()
+	if err := saveJSON(providerStatusFile, status); err != nil {
+		logVerbose("provider status: %v", err)
+	}
+}
+
+// handleProviders probes every backend and shows which ones are answering.
+func handleProviders() {
+	roles := providerRoles()
+
+	var lastSuccess map[string]time.Time
+	if err := loadJSON(providerStatusFile, &lastSuccess); err != nil {
+		printWarning("Warning: %v\n", err)
+	}
+
+	latencies := make([]time.Duration, len(roles))
+	errs := make([]error, len(roles))
+	WithSpinner("Probing providers...", func() error {
+		client := newHTTPClient(5 * time.Second)
+		var wg sync.WaitGroup
+		for i, role := range roles {
+			wg.Add(1)
+			go func(i int, host string) {
+				defer wg.Done()
+				_, latencies[i], errs[i] = probeHost(client, host)
+			}(i, role.Host)
+		}
+		wg.Wait()
+		return nil
+	})
+
+	fmt.Println()
+	printTitle("%s Providers\n", iconNetwork(""))
+
+	table := NewTable("Role", "Provider", "Status", "Latency", "Last success", "Fallback").SetTruncate(1, 28)
+	down := 0
+	for i, role := range roles {
+		status, latency := colorGreen("up"), latencies[i].String()
+		if errs[i] != nil {
+			status, latency = colorRed("down"), ""
+			down++
+		}
+
+		last := "never"
+		if when, ok := lastSuccess[role.Host]; ok {
+			last = formatAgo(when)
+		}
+		table.AddRow(role.Role, role.Host, status, latency, last, role.Fallback)
+	}
+	table.Print()
+
+	fmt.Println()
+	if down > 0 {
+		printWarning("%d provider(s) unreachable, run `nomad doctor` for suggested fixes\n", down)
+		return
+	}
+	printSuccess("All providers are answering\n")
+}
+
+// formatAgo describes how long ago t was, e.g. "5m ago".
+func formatAgo(t time.Time) string {
+	ago := time.Since(t)
+	switch {
+	case ago < time.Minute:
+		return "just now"
+	case ago < time.Hour:
+		return fmt.Sprintf("%dm ago", int(ago.Minutes()))
+	case ago < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(ago.Hours()))
+	default:
+		return t.Format("Jan 2")
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// printQRCode renders text as a QR code using half-block characters, so
+// each character cell holds two modules and the code fits in a normal
+// terminal. Dark modules are drawn as the terminal background; set
+// qr_invert in the config when using a light background.
+func printQRCode(text string) error {
+	code, err := qrcode.New(text, qrcode.Medium)
+	if err != nil {
+		return fmt.Errorf("failed to generate QR code: %v", err)
+	}
+
+	fmt.Println()
+	fmt.Print(code.ToSmallString(config.Bool("qr_invert")))
+	return nil
+}
+
+// openLink opens url in the default browser, or prints it with a QR code
+// for opening on a phone when asQR is set.
+func openLink(url string, asQR bool) error {
+	if !asQR {
+		return OpenBrowser(url)
+	}
+
+	if err := printQRCode(url); err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", colorCyan(url))
+	return nil
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RateProvider is a source of the latest exchange rates.
+type RateProvider interface {
+	// Name is the identifier used in config and with --provider
+	Name() string
+	// Host is the API hostname, for DNS warmup and health checks
+	Host() string
+	// Latest returns the rates of every currency against base
+	Latest(base string) (*ExchangeRateResponse, error)
+}
+
+// rateProviders lists the available providers by name.
+var rateProviders = map[string]func() (RateProvider, error){
+	"exchangerate-api": func() (RateProvider, error) { return exchangeRateAPI{}, nil },
+	"frankfurter":      func() (RateProvider, error) { return frankfurter{}, nil },
+	"openexchangerates": func() (RateProvider, error) {
+		key := providerKey("openexchangerates_key", "OPENEXCHANGERATES_APP_ID")
+		if key == "" {
+			return nil, fmt.Errorf("openexchangerates needs an App ID in openexchangerates_key or OPENEXCHANGERATES_APP_ID")
+		}
+		return openExchangeRates{appID: key}, nil
+	},
+	"fixer": func() (RateProvider, error) {
+		key := providerKey("fixer_key", "FIXER_ACCESS_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("fixer needs an access key in fixer_key or FIXER_ACCESS_KEY")
+		}
+		return fixer{accessKey: key}, nil
+	},
+}
+
+// defaultRateProvider needs no account and covers the most currencies.
+const defaultRateProvider = "exchangerate-api"
+
+// rateProviderTimeout bounds each provider's request, so a hanging
+// provider leaves time to try the next one.
+const rateProviderTimeout = 6 * time.Second
+
+// ratesCacheTTL is how long fetched rates are reused. Free plans update
+// them at most hourly.
+const ratesCacheTTL = time.Hour
+
+// rateSnapshot holds the USD rates of major currencies at build time, so
+// conversions still give an approximate answer with no connectivity and an
+// empty cache. Refresh it before each release with `go generate`.
+//
+//go:generate go run snapshot_gen.go
+//go:embed snapshot/rates-usd.json
+var rateSnapshot []byte
+
+var (
+	// snapshotFallback lets getExchangeRates use rateSnapshot when every
+	// live source fails. Commands enable it when an approximate rate is
+	// better than none and they can say so.
+	snapshotFallback bool
+	// snapshotUsed is the snapshot's date when it stood in for live rates
+	// in the latest lookup
+	snapshotUsed string
+)
+
+// snapshotRates returns the embedded snapshot rebased onto base.
+func snapshotRates(base string) (*ExchangeRateResponse, error) {
+	var rates ExchangeRateResponse
+	if err := json.Unmarshal(rateSnapshot, &rates); err != nil {
+		return nil, fmt.Errorf("invalid rate snapshot: %v", err)
+	}
+	return rebaseRates(&rates, base)
+}
+
+// printSnapshotNotice labels results that were computed from the snapshot
+// or came from a fallback provider.
+func printSnapshotNotice() {
+	if rateFallbackReason != "" && snapshotUsed == "" {
+		printWarning("  Rates from %s: %s\n", rateSource, rateFallbackReason)
+	}
+	if snapshotUsed == "" {
+		return
+	}
+	date := snapshotUsed
+	if t, err := time.Parse("2006-01-02", snapshotUsed); err == nil {
+		date = t.Format("Jan 2, 2006")
+	}
+	printWarning("  Offline: approximate rates from the built-in snapshot of %s\n", date)
+}
+
+// addRateSource records the provider of the last rate lookup for
+// printSources.
+func addRateSource() {
+	source := Source{What: "Exchange rate", From: rateSource, Confidence: confidenceHigh, Updated: rateDate}
+	switch {
+	case snapshotUsed != "":
+		source.Confidence = confidenceLow
+	case rateFallbackReason != "" || crossRateVia != "":
+		source.Confidence = confidenceMedium
+	}
+	addSource(source)
+}
+
+// rateProviderNames returns the provider names in alphabetical order.
+func rateProviderNames() []string {
+	names := make([]string, 0, len(rateProviders))
+	for name := range rateProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// currentRateProvider returns the provider chosen with --provider or the
+// rate_provider config key.
+func currentRateProvider() (RateProvider, error) {
+	name := options.RateProvider
+	if name == "" {
+		name = defaultRateProvider
+	}
+
+	newProvider, ok := rateProviders[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown rate provider '%s' (use %s)", name, strings.Join(rateProviderNames(), ", "))
+	}
+	return newProvider()
+}
+
+// rateFallbackOrder is the order providers are tried in after the chosen
+// one fails. Providers needing a key are skipped when it isn't set.
+var rateFallbackOrder = []string{"exchangerate-api", "frankfurter", "openexchangerates", "fixer"}
+
+// rateFallbackReason says why the chosen provider was passed over, when a
+// fallback answered the latest lookup.
+var rateFallbackReason string
+
+// rateProviderChain returns the chosen provider followed by every other
+// provider that can be used, in rateFallbackOrder.
+func rateProviderChain() ([]RateProvider, error) {
+	chosen, err := currentRateProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []RateProvider{chosen}
+	for _, name := range rateFallbackOrder {
+		if name == chosen.Name() {
+			continue
+		}
+		if provider, err := rateProviders[name](); err == nil {
+			chain = append(chain, provider)
+		}
+	}
+	return chain, nil
+}
+
+// latestFromChain asks each provider in turn for rates against base,
+// returning the first answer and the name of the provider that gave it.
+// The error is the chosen provider's when every one fails.
+func latestFromChain(base string) (*ExchangeRateResponse, string, error) {
+	chain, err := rateProviderChain()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var firstErr error
+	for i, provider := range chain {
+		rates, err := provider.Latest(base)
+		if err == nil {
+			if i > 0 {
+				rateFallbackReason = fmt.Sprintf("%s failed (%v)", chain[0].Name(), firstErr)
+				if isSchemaError(firstErr) {
+					rateFallbackReason += "; run `nomad doctor` if this keeps happening"
+				}
+			}
+			return rates, provider.Name(), nil
+		}
+		logVerbose("%s failed: %v", provider.Name(), err)
+		if i == 0 {
+			firstErr = err
+		}
+	}
+	return nil, chain[0].Name(), firstErr
+}
+
+// providerKey reads an API key from the config, falling back to an
+// environment variable so keys can stay out of the config file.
+func providerKey(configKey, envVar string) string {
+	if key := config.Get(configKey); key != "" {
+		return key
+	}
+	return os.Getenv(envVar)
+}
+
+// fetchRatesJSON downloads a provider response through the shared cache,
+// keeping it only when decode accepts it.
+func fetchRatesJSON(cacheKey, url string, decode func(body []byte) error) error {
+	return cachedDecode(cacheKey, ratesCacheTTL, func() ([]byte, error) {
+		client := newHTTPClient(rateProviderTimeout)
+
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch exchange rate: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("API returned status code: %d", resp.StatusCode)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %v", err)
+		}
+		return body, nil
+	}, decode)
+}
+
+// rebaseRates converts rates quoted against one currency to rates against
+// base, for plans that only serve a fixed base currency.
+func rebaseRates(rates *ExchangeRateResponse, base string) (*ExchangeRateResponse, error) {
+	base = strings.ToUpper(base)
+	if rates.Base == base {
+		return rates, nil
+	}
+
+	baseRate, ok := rates.Rates[base]
+	if !ok || baseRate == 0 {
+		return nil, fmt.Errorf("currency '%s' not found in exchange rates", base)
+	}
+
+	rebased := &ExchangeRateResponse{Base: base, Date: rates.Date, Rates: make(map[string]float64, len(rates.Rates))}
+	for code, rate := range rates.Rates {
+		rebased.Rates[code] = rate / baseRate
+	}
+	return rebased, nil
+}
+
+// exchangeRateAPI is the free, keyless exchangerate-api.com v4 endpoint.
+type exchangeRateAPI struct{}
+
+func (exchangeRateAPI) Name() string { return "exchangerate-api" }
+func (exchangeRateAPI) Host() string { return "api.exchangerate-api.com" }
+
+func (exchangeRateAPI) Latest(base string) (*ExchangeRateResponse, error) {
+	var response ExchangeRateResponse
+	err := fetchRatesJSON("rates:"+base, fmt.Sprintf("https://api.exchangerate-api.com/v4/latest/%s", base), func(body []byte) error {
+		if err := json.Unmarshal(body, &response); err != nil {
+			return schemaMismatch("exchangerate-api", "invalid JSON: "+err.Error(), body)
+		}
+		if problem := ratesProblem(&response); problem != "" {
+			return schemaMismatch("exchangerate-api", problem, body)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// frankfurter serves European Central Bank reference rates, keyless but
+// limited to about 30 major currencies.
+type frankfurter struct{}
+
+func (frankfurter) Name() string { return "frankfurter" }
+func (frankfurter) Host() string { return "api.frankfurter.app" }
+
+func (frankfurter) Latest(base string) (*ExchangeRateResponse, error) {
+	var response ExchangeRateResponse
+	err := fetchRatesJSON("rates:frankfurter:"+base, fmt.Sprintf("https://api.frankfurter.app/latest?from=%s", base), func(body []byte) error {
+		if err := json.Unmarshal(body, &response); err != nil {
+			return schemaMismatch("frankfurter", "invalid JSON: "+err.Error(), body)
+		}
+		if problem := ratesProblem(&response); problem != "" {
+			return schemaMismatch("frankfurter", problem, body)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The base currency itself is left out of the rates
+	response.Rates[response.Base] = 1
+	return &response, nil
+}
+
+// openExchangeRates uses openexchangerates.org. Free plans only quote
+// against USD, so other bases are derived locally.
+type openExchangeRates struct {
+	appID string
+}
+
+func (openExchangeRates) Name() string { return "openexchangerates" }
+func (openExchangeRates) Host() string { return "openexchangerates.org" }
+
+func (p openExchangeRates) Latest(base string) (*ExchangeRateResponse, error) {
+	var rates *ExchangeRateResponse
+	err := fetchRatesJSON("rates:openexchangerates:USD", "https://openexchangerates.org/api/latest.json?app_id="+p.appID, func(body []byte) error {
+		var response struct {
+			Timestamp   int64              `json:"timestamp"`
+			Base        string             `json:"base"`
+			Rates       map[string]float64 `json:"rates"`
+			Description string             `json:"description"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return schemaMismatch("openexchangerates", "invalid JSON: "+err.Error(), body)
+		}
+		if len(response.Rates) == 0 && response.Description != "" {
+			return fmt.Errorf("openexchangerates: %s", response.Description)
+		}
+
+		rates = &ExchangeRateResponse{
+			Base:  response.Base,
+			Date:  time.Unix(response.Timestamp, 0).UTC().Format("2006-01-02"),
+			Rates: response.Rates,
+		}
+		if problem := ratesProblem(rates); problem != "" {
+			return schemaMismatch("openexchangerates", problem, body)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rebaseRates(rates, base)
+}
+
+// fixer uses fixer.io. Free plans only quote against EUR, so other bases
+// are derived locally, and only over plain HTTP, which would send the key
+// in the clear; nomad asks over HTTPS, so it needs a paid plan.
+type fixer struct {
+	accessKey string
+}
+
+func (fixer) Name() string { return "fixer" }
+func (fixer) Host() string { return "data.fixer.io" }
+
+func (p fixer) Latest(base string) (*ExchangeRateResponse, error) {
+	var rates *ExchangeRateResponse
+	err := fetchRatesJSON("rates:fixer:EUR", "https://data.fixer.io/api/latest?access_key="+p.accessKey, func(body []byte) error {
+		var response struct {
+			Success bool               `json:"success"`
+			Base    string             `json:"base"`
+			Date    string             `json:"date"`
+			Rates   map[string]float64 `json:"rates"`
+			Error   struct {
+				Code int    `json:"code"`
+				Type string `json:"type"`
+				Info string `json:"info"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return schemaMismatch("fixer", "invalid JSON: "+err.Error(), body)
+		}
+		// Failures come back with status 200 and success set to false
+		if !response.Success {
+			switch {
+			case response.Error.Type == "https_access_restricted":
+				return fmt.Errorf("fixer: your plan doesn't include HTTPS, which nomad needs to keep the key private (use a paid fixer plan)")
+			case response.Error.Info != "":
+				return fmt.Errorf("fixer: %s", response.Error.Info)
+			case response.Error.Type != "":
+				return fmt.Errorf("fixer: %s (code %d)", response.Error.Type, response.Error.Code)
+			}
+			return schemaMismatch("fixer", "success is false with no error", body)
+		}
+
+		rates = &ExchangeRateResponse{Base: response.Base, Date: response.Date, Rates: response.Rates}
+		if problem := ratesProblem(rates); problem != "" {
+			return schemaMismatch("fixer", problem, body)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rebaseRates(rates, base)
+}
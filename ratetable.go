@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// handleRateTable implements `nomad cv table [base] [codes...] [--all]`,
+// listing many rates against one base currency at once. Without codes it
+// shows the rate_table shortlist from the config, or every currency.
+func handleRateTable(args []string) {
+	args, all := popFlag(args, "--all")
+
+	base := strings.ToUpper(config.Get("home_currency"))
+	if len(args) > 0 {
+		base, args = mustResolveCurrency(args[0]), args[1:]
+	}
+	if base == "" {
+		base = "USD"
+	}
+	if !isCurrencyCode(base) || isCrypto(base) {
+		printError("Error: Invalid base currency '%s'\n", base)
+		printInfo("Example: nomad cv table usd [eur gbp thb] [--all]\n")
+		os.Exit(1)
+	}
+
+	codes := args
+	if len(codes) == 0 && !all && config.Get("rate_table") != "" {
+		codes = strings.Split(config.Get("rate_table"), ",")
+	}
+
+	snapshotFallback = true
+	var rates *ExchangeRateResponse
+	err := WithSpinner("Fetching exchange rates...", func() error {
+		var fetchErr error
+		rates, fetchErr = getExchangeRates(base)
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error getting exchange rates: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	printTitle("%s Rates for 1 %s\n", iconCurrency(""), base)
+	if rates.Date != "" {
+		printInfo("  As of %s\n", rates.Date)
+	}
+	fmt.Println()
+
+	if len(codes) == 0 {
+		printRateGrid(rates)
+	} else {
+		printRateShortlist(rates, codes)
+	}
+	printSnapshotNotice()
+}
+
+// printRateShortlist shows the chosen currencies with their names and the
+// rate in both directions.
+func printRateShortlist(rates *ExchangeRateResponse, codes []string) {
+	table := NewTable("Currency", "", "Rate", "Inverse").SetTruncate(1, 24)
+	var missing []string
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || code == rates.Base {
+			continue
+		}
+		rate, ok := rates.Rates[code]
+		if !ok || rate == 0 {
+			missing = append(missing, code)
+			continue
+		}
+
+		var name string
+		if currency := findCurrency(code); currency != nil {
+			name = currency.Name
+		}
+		table.AddRow(colorBold(code), name, colorYellow(formatGrouped(rate)), colorCyan(formatRate(1/rate)))
+	}
+	table.Print()
+
+	if len(missing) > 0 {
+		fmt.Println()
+		printWarning("  No rate for %s\n", strings.Join(missing, ", "))
+	}
+}
+
+// printRateGrid lays every rate out in as many columns as fit the terminal,
+// sorted by code and read top to bottom like ls.
+func printRateGrid(rates *ExchangeRateResponse) {
+	codes := make([]string, 0, len(rates.Rates))
+	for code := range rates.Rates {
+		if code != rates.Base {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	cells := make([]string, len(codes))
+	cellWidth := 0
+	for i, code := range codes {
+		cells[i] = code + "  " + formatGrouped(rates.Rates[code])
+		cellWidth = max(cellWidth, displayWidth(cells[i]))
+	}
+	if len(cells) == 0 {
+		return
+	}
+
+	const gap = 4
+	columns := max(1, (terminalWidth()-2+gap)/(cellWidth+gap))
+	rowCount := (len(cells) + columns - 1) / columns
+
+	var b strings.Builder
+	for row := 0; row < rowCount; row++ {
+		b.WriteString("  ")
+		for col := 0; col < columns; col++ {
+			i := col*rowCount + row
+			if i >= len(cells) {
+				break
+			}
+			code, value, _ := strings.Cut(cells[i], "  ")
+			cell := colorBold(code) + "  " + colorYellow(value)
+			if col < columns-1 && (col+1)*rowCount+row < len(cells) {
+				cell = padRight(cell, cellWidth+gap)
+			}
+			b.WriteString(cell)
+		}
+		b.WriteString("\n")
+	}
+	Page(b.String())
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recordedCommand is written to command.json in a recording directory so
+// the same invocation can be replayed later.
+type recordedCommand struct {
+	Args       []string  `json:"args"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// recordedExchange is a single API request and its raw response.
+type recordedExchange struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// sensitiveParams are query parameters stripped from recorded URLs so that
+// fixtures can be attached to public issues. Coordinates are among them, as
+// commands run without a city pass on the IP-based location.
+var sensitiveParams = []string{"key", "apikey", "api_key", "appid", "app_id", "access_key", "token", "secret",
+	"latitude", "longitude", "lat", "lon"}
+
+// locationFields are JSON fields in recorded bodies that can place the
+// user, such as wttr.in's nearest_area for an IP-based lookup.
+var locationFields = map[string]bool{
+	"areaName": true, "region": true, "regionName": true, "city": true, "town": true, "village": true,
+	"suburb": true, "road": true, "postcode": true, "zip": true, "display_name": true,
+	"latitude": true, "longitude": true, "lat": true, "lon": true, "coord": true, "query": true, "ip": true,
+}
+
+const recordedCommandFile = "command.json"
+
+// sanitizeURL redacts credentials from a request URL.
+func sanitizeURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+
+	query := clean.Query()
+	for name := range query {
+		for _, sensitive := range sensitiveParams {
+			if strings.EqualFold(name, sensitive) {
+				query.Set(name, "REDACTED")
+			}
+		}
+	}
+	clean.RawQuery = query.Encode()
+
+	return clean.String()
+}
+
+// redactLocations blanks the locationFields of a JSON body, and the names
+// of objects holding coordinates. Values keep their types so the recording
+// still replays: numbers become 0 and text "REDACTED". Other bodies are
+// returned unchanged.
+func redactLocations(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+	if !redactLocationFields(value) {
+		return body
+	}
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactLocationFields redacts value in place and reports whether anything
+// was redacted.
+func redactLocationFields(value interface{}) bool {
+	changed := false
+	switch value := value.(type) {
+	case map[string]interface{}:
+		placed := false
+		for _, key := range []string{"lat", "lon", "latitude", "longitude", "coord"} {
+			if _, ok := value[key]; ok {
+				placed = true
+			}
+		}
+		for key, field := range value {
+			if locationFields[key] || (placed && key == "name") {
+				value[key] = redactedValue(field)
+				changed = true
+			} else if redactLocationFields(field) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if redactLocationFields(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// redactedValue replaces every string and number in value with a
+// placeholder of the same type.
+func redactedValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			value[key] = redactedValue(field)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactedValue(item)
+		}
+		return value
+	case json.Number:
+		return json.Number("0")
+	case string:
+		// wttr.in sends coordinates as strings
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return "0"
+		}
+		return "REDACTED"
+	}
+	return value
+}
+
+// startRecording prepares dir to receive the responses of the command
+// described by args.
+func startRecording(dir string, args []string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create recording directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(recordedCommand{Args: args, RecordedAt: time.Now()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, recordedCommandFile), data, 0o644)
+}
+
+// recordingTransport saves every API response it sees to a fixture
+// directory, numbered in request order.
+type recordingTransport struct {
+	next http.RoundTripper
+	dir  string
+
+	mu    sync.Mutex
+	count int
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	exchange := recordedExchange{Method: req.Method, URL: sanitizeURL(req.URL)}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		exchange.Error = err.Error()
+		t.save(req.URL.Host, exchange)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	exchange.Status = resp.StatusCode
+	exchange.ContentType = resp.Header.Get("Content-Type")
+	exchange.Body = string(redactLocations(body))
+	t.save(req.URL.Host, exchange)
+
+	return resp, nil
+}
+
+// save writes one exchange to disk. Failures are reported but never break
+// the command being recorded.
+func (t *recordingTransport) save(host string, exchange recordedExchange) {
+	t.mu.Lock()
+	t.count++
+	name := fmt.Sprintf("%03d-%s.json", t.count, host)
+	t.mu.Unlock()
+
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(t.dir, name), data, 0o644)
+	}
+	if err != nil {
+		printWarning("Warning: failed to record response: %v\n", err)
+	}
+}
+
+// replayTransport answers requests from a recording directory instead of the
+// network. Repeated requests for the same URL are served in recorded order.
+type replayTransport struct {
+	mu        sync.Mutex
+	exchanges map[string][]recordedExchange
+}
+
+// loadReplayTransport reads every recorded exchange in dir.
+func loadReplayTransport(dir string) (*replayTransport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	t := &replayTransport{exchanges: make(map[string][]recordedExchange)}
+	for _, file := range files {
+		if filepath.Base(file) == recordedCommandFile {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %v", err)
+		}
+
+		var exchange recordedExchange
+		if err := json.Unmarshal(data, &exchange); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %v", filepath.Base(file), err)
+		}
+
+		key := exchange.Method + " " + exchange.URL
+		t.exchanges[key] = append(t.exchanges[key], exchange)
+	}
+
+	return t, nil
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + sanitizeURL(req.URL)
+
+	t.mu.Lock()
+	queue := t.exchanges[key]
+	if len(queue) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("no recorded response for %s", key)
+	}
+	exchange := queue[0]
+	// Keep the last response around so extra identical requests still work
+	if len(queue) > 1 {
+		t.exchanges[key] = queue[1:]
+	}
+	t.mu.Unlock()
+
+	if exchange.Error != "" {
+		return nil, errors.New(exchange.Error)
+	}
+
+	return fixtureResponse(req, exchange.Status, exchange.ContentType, exchange.Body), nil
+}
+
+// fixtureResponse builds the response to req from a saved or canned body.
+func fixtureResponse(req *http.Request, status int, contentType, body string) *http.Response {
+	header := make(http.Header)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// replayer is set when a recording is being replayed; it replaces the
+// network transport for every HTTP client.
+var replayer http.RoundTripper
+
+// handleReplay re-runs a recorded command against its saved responses.
+func handleReplay(args []string) {
+	if len(args) < 1 {
+		printError("Usage: nomad replay <dir>\n")
+		printInfo("Example: nomad replay ./weather-bug\n")
+		os.Exit(1)
+	}
+	dir := args[0]
+
+	data, err := os.ReadFile(filepath.Join(dir, recordedCommandFile))
+	if err != nil {
+		printError("Error: %s is not a recording (missing %s)\n", dir, recordedCommandFile)
+		os.Exit(1)
+	}
+
+	var recorded recordedCommand
+	if err := json.Unmarshal(data, &recorded); err != nil || len(recorded.Args) == 0 {
+		printError("Error: invalid %s in %s\n", recordedCommandFile, dir)
+		os.Exit(1)
+	}
+
+	transport, err := loadReplayTransport(dir)
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+	replayer = transport
+
+	printInfo("Replaying 'nomad %s' recorded %s\n", strings.Join(recorded.Args, " "), recorded.RecordedAt.Format("2006-01-02 15:04"))
+	runCommand(recorded.Args[0], recorded.Args[1:])
+}
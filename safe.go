@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// handleSafe summarises tap water, ice and food safety for a country, e.g.
+// `nomad safe thailand`.
+func handleSafe(args []string) {
+	if len(args) < 1 {
+		printError("Usage: nomad safe <country>\n")
+		printInfo("Example: nomad safe thailand\n")
+		os.Exit(1)
+	}
+
+	country := findCountry(strings.Join(args, " "))
+	if country == nil {
+		printError("Error: No food safety notes for '%s'\n", strings.Join(args, " "))
+		printInfo("Known countries: %s\n", strings.Join(countryNames(), ", "))
+		os.Exit(1)
+	}
+	safety := country.Safety
+
+	announceResult("Tap water in %s: %s", country.Name, stripANSI(tapWaterSummary(safety)))
+
+	fmt.Println()
+	printTitle("%s Eating and drinking in %s\n", iconInfo(""), country.Name)
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Tap water"), 14), tapWaterSummary(safety))
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Ice"), 14), safety.Ice)
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Food"), 14), safety.Food)
+	addSource(Source{What: "Safety advice", From: builtInData, Confidence: confidenceMedium,
+		Check: "general advice for visitors, and local conditions vary"})
+	printSources()
+}
+
+// tapWaterSummary describes whether tap water is drinkable, colored by how
+// careful to be.
+func tapWaterSummary(safety FoodSafety) string {
+	var summary string
+	switch safety.Water {
+	case "safe":
+		summary = colorGreen("safe to drink")
+	case "caution":
+		summary = colorYellow("drinkable in places")
+	default:
+		summary = colorRed("not safe to drink")
+	}
+	if safety.WaterNote != "" {
+		summary += " · " + safety.WaterNote
+	}
+	return summary
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const diagnosticsFile = "diagnostics.log"
+
+// Diagnostics are kept to a size worth attaching to a bug report.
+const (
+	diagnosticsMaxPayload = 4096
+	diagnosticsMaxSize    = 1 << 20
+)
+
+// SchemaError reports a provider response that no longer has the shape
+// nomad expects, usually because the API changed or was retired.
+type SchemaError struct {
+	Provider string
+	Problem  string
+	// Log is the diagnostics file the payload was written to, if any
+	Log string
+}
+
+func (e *SchemaError) Error() string {
+	msg := fmt.Sprintf("%s answered in an unexpected format (%s)", e.Provider, e.Problem)
+	if e.Log != "" {
+		msg += "; the response was saved to " + e.Log
+	}
+	return msg
+}
+
+// diagnosticEntry is one line of the diagnostics file.
+type diagnosticEntry struct {
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"`
+	Problem  string    `json:"problem"`
+	Payload  string    `json:"payload"`
+}
+
+// schemaMismatch records a response that failed validation in the
+// diagnostics file and returns the error describing it. Mock runs are not
+// recorded.
+func schemaMismatch(provider, problem string, payload []byte) error {
+	schemaErr := &SchemaError{Provider: provider, Problem: problem}
+	logVerbose("%s: unexpected response: %s", provider, problem)
+	if options.Mock {
+		return schemaErr
+	}
+
+	path, err := dataPath(diagnosticsFile)
+	if err != nil {
+		return schemaErr
+	}
+	// Start over rather than let the log grow without bound
+	if info, err := os.Stat(path); err == nil && info.Size() > diagnosticsMaxSize {
+		os.Remove(path)
+	}
+
+	if len(payload) > diagnosticsMaxPayload {
+		payload = payload[:diagnosticsMaxPayload]
+	}
+	line, err := json.Marshal(diagnosticEntry{Time: time.Now(), Provider: provider, Problem: problem, Payload: string(payload)})
+	if err != nil {
+		return schemaErr
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return schemaErr
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err == nil {
+		schemaErr.Log = path
+	}
+	return schemaErr
+}
+
+// isSchemaError reports whether err came from a response that failed
+// validation.
+func isSchemaError(err error) bool {
+	var schemaErr *SchemaError
+	return errors.As(err, &schemaErr)
+}
+
+// recentDiagnostics returns the entries logged since the given time.
+func recentDiagnostics(since time.Time) ([]diagnosticEntry, error) {
+	path, err := dataPath(diagnosticsFile)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []diagnosticEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), diagnosticsMaxPayload*8)
+	for scanner.Scan() {
+		var entry diagnosticEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.Time.After(since) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// ratesProblem checks a parsed rates response, returning what is wrong
+// with it or "" if it looks usable.
+func ratesProblem(rates *ExchangeRateResponse) string {
+	switch {
+	case rates.Base == "":
+		return "no base currency"
+	case len(rates.Rates) == 0:
+		return "no rates"
+	}
+	for code, rate := range rates.Rates {
+		if rate <= 0 {
+			return fmt.Sprintf("invalid rate for %s", code)
+		}
+	}
+	return ""
+}
+
+// weatherProblem checks a parsed wttr.in response the same way.
+func weatherProblem(response *WttrResponse) string {
+	if _, ok := response.Current(); !ok {
+		return "no current_condition"
+	}
+	if len(response.Weather) == 0 {
+		return "no weather forecast days"
+	}
+	if _, ok := response.Area(); !ok {
+		return "no nearest_area"
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+//go:build ignore
+
+// snapshot_gen refreshes snapshot/rates-usd.json, the exchange rates built
+// into the binary for offline use. Run it with `go generate` before a
+// release.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// snapshotCurrencies are the currencies kept in the snapshot: the majors
+// plus those of popular nomad destinations.
+var snapshotCurrencies = []string{
+	"AED", "ARS", "AUD", "BGN", "BRL", "CAD", "CHF", "CLP", "CNY", "COP",
+	"CZK", "DKK", "EGP", "EUR", "GBP", "GEL", "HKD", "HUF", "IDR", "ILS",
+	"INR", "ISK", "JPY", "KHR", "KRW", "LAK", "LKR", "MAD", "MXN", "MYR",
+	"NOK", "NPR", "NZD", "PEN", "PHP", "PLN", "RON", "RSD", "SEK", "SGD",
+	"THB", "TRY", "TWD", "UAH", "USD", "VND", "ZAR",
+}
+
+type ratesResponse struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "snapshot_gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get("https://api.exchangerate-api.com/v4/latest/USD")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status code: %d", resp.StatusCode)
+	}
+
+	var latest ratesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&latest); err != nil {
+		return fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+
+	snapshot := ratesResponse{Base: latest.Base, Date: latest.Date, Rates: make(map[string]float64)}
+	for _, code := range snapshotCurrencies {
+		rate, ok := latest.Rates[code]
+		if !ok {
+			return fmt.Errorf("no rate for %s", code)
+		}
+		snapshot.Rates[code] = rate
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile("snapshot/rates-usd.json", append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("snapshot/rates-usd.json updated to %s\n", snapshot.Date)
+	return nil
+}
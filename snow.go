@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// snowCacheTTL is how long a snow forecast is reused.
+const snowCacheTTL = time.Hour
+
+// powderDayCm is the fresh snowfall in a day that makes it worth getting up
+// early for.
+const powderDayCm = 15
+
+// SnowForecast is Open-Meteo's hourly snow data for a point, from a day ago
+// to a few days ahead.
+type SnowForecast struct {
+	UTCOffsetSeconds int `json:"utc_offset_seconds"`
+	Hourly           struct {
+		Time []string `json:"time"`
+		// SnowDepth is in metres, Snowfall in centimetres per hour and
+		// FreezingLevel in metres above sea level
+		SnowDepth     []*float64 `json:"snow_depth"`
+		Snowfall      []*float64 `json:"snowfall"`
+		FreezingLevel []*float64 `json:"freezing_level_height"`
+	} `json:"hourly"`
+	Daily struct {
+		Time     []string   `json:"time"`
+		Snowfall []*float64 `json:"snowfall_sum"`
+	} `json:"daily"`
+}
+
+// nowIndex returns the index of the current hour at the location.
+func (f *SnowForecast) nowIndex() int {
+	now := time.Now().UTC().Add(time.Duration(f.UTCOffsetSeconds) * time.Second).Format("2006-01-02T15:00")
+	for i, t := range f.Hourly.Time {
+		if t == now {
+			return i
+		}
+	}
+	return -1
+}
+
+// getSnowForecast fetches snow depth, snowfall and the freezing level from
+// Open-Meteo, which unlike wttr.in covers them for mountain resorts.
+func getSnowForecast(lat, lon float64) (*SnowForecast, error) {
+	var forecast SnowForecast
+	err := fetchCachedJSON(fmt.Sprintf("snow:%.2f,%.2f", lat, lon),
+		fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&hourly=snow_depth,snowfall,freezing_level_height&daily=snowfall_sum&past_days=1&forecast_days=3&timezone=auto",
+			lat, lon), snowCacheTTL,
+		func(body []byte) error {
+			if err := json.Unmarshal(body, &forecast); err != nil {
+				return schemaMismatch("api.open-meteo.com", "invalid JSON: "+err.Error(), body)
+			}
+			if len(forecast.Hourly.Time) == 0 || len(forecast.Hourly.SnowDepth) != len(forecast.Hourly.Time) {
+				return schemaMismatch("api.open-meteo.com", "no hourly snow data", body)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &forecast, nil
+}
+
+// printSnowReport adds the snow report for `nomad weather --snow`.
+func printSnowReport(weather *Weather) {
+	if !weather.HasCoords {
+		printWarning("  Snow report unavailable: no coordinates for this location\n")
+		return
+	}
+
+	var forecast *SnowForecast
+	err := WithSpinner("Fetching snow report...", func() error {
+		var fetchErr error
+		forecast, fetchErr = getSnowForecast(weather.Lat, weather.Lon)
+		return fetchErr
+	})
+	if err != nil {
+		printWarning("  Snow report unavailable: %v\n", err)
+		return
+	}
+	now := forecast.nowIndex()
+	if now < 0 {
+		printWarning("  Snow report unavailable: no data for the current hour\n")
+		return
+	}
+
+	value := func(values []*float64, i int) (float64, bool) {
+		if i < 0 || i >= len(values) || values[i] == nil {
+			return 0, false
+		}
+		return *values[i], true
+	}
+	var fresh float64
+	for i := max(0, now-23); i <= now; i++ {
+		snowfall, _ := value(forecast.Hourly.Snowfall, i)
+		fresh += snowfall
+	}
+
+	fmt.Println()
+	printTitle("%s Snow report\n", iconWeather(""))
+	if depth, ok := value(forecast.Hourly.SnowDepth, now); ok {
+		fmt.Printf("  %s %s\n", padRight(iconInfo("Snow depth"), 14), colorYellow(formatCm(depth*100)))
+	}
+	freshText := formatCm(fresh)
+	if fresh >= powderDayCm {
+		freshText = colorGreen(freshText + " · powder day")
+	}
+	fmt.Printf("  %s %s %s\n", padRight(iconInfo("Fresh snow"), 14), freshText, colorCyan("(last 24 hours)"))
+	if level, ok := value(forecast.Hourly.FreezingLevel, now); ok {
+		fmt.Printf("  %s %s\n", padRight(iconInfo("Freezing level"), 14), colorYellow(formatMeters(level)))
+	}
+
+	// The first daily entry is yesterday, from past_days
+	today := time.Now().UTC().Add(time.Duration(forecast.UTCOffsetSeconds) * time.Second).Format("2006-01-02")
+	table := NewTable("Day", "Snowfall")
+	for i, day := range forecast.Daily.Time {
+		if day < today {
+			continue
+		}
+		snowfall, ok := value(forecast.Daily.Snowfall, i)
+		if !ok {
+			continue
+		}
+		label := day
+		if date, err := time.Parse("2006-01-02", day); err == nil {
+			label = date.Format("Mon, Jan 2")
+		}
+		amount := formatCm(snowfall)
+		switch {
+		case snowfall >= powderDayCm:
+			amount = colorGreen(amount + " · powder")
+		case math.Round(snowfall) == 0:
+			amount = colorCyan(amount)
+		}
+		table.AddRow(label, amount)
+	}
+	fmt.Println()
+	table.Print()
+	addSource(Source{What: "Snow", From: "Open-Meteo", Confidence: confidenceMedium,
+		Check: "a model forecast for the location, so check the resort's own snow report for the slopes"})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// speechCommand returns the text-to-speech command for the current
+// platform. The text is passed on stdin.
+func speechCommand() (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("say"), nil
+	case "windows":
+		script := "Add-Type -AssemblyName System.Speech; " +
+			"(New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak([Console]::In.ReadToEnd())"
+		return exec.Command("powershell", "-NoProfile", "-Command", script), nil
+	default:
+		for _, name := range []string{"espeak-ng", "espeak"} {
+			if _, err := exec.LookPath(name); err == nil {
+				return exec.Command(name, "--stdin"), nil
+			}
+		}
+		return nil, fmt.Errorf("no text-to-speech tool found (install espeak-ng or espeak)")
+	}
+}
+
+// speech is the text-to-speech process still reading a result aloud.
+var speech *exec.Cmd
+
+// Speak starts reading text aloud in the background; waitForSpeech blocks
+// until it has finished.
+func Speak(text string) error {
+	cmd, err := speechCommand()
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("text-to-speech failed: %v", err)
+	}
+	speech = cmd
+	return nil
+}
+
+// waitForSpeech lets a result being read aloud finish before nomad exits.
+func waitForSpeech() {
+	if speech != nil {
+		speech.Wait()
+	}
+}
+
+// announceResult states a command's most important fact as a sentence. With
+// --plain it is printed before the details, and with --speak it is read
+// aloud while they are shown.
+func announceResult(format string, args ...interface{}) {
+	sentence := fmt.Sprintf(format, args...)
+
+	if options.Plain {
+		fmt.Println(sentence)
+	}
+	if options.Speak {
+		if err := Speak(sentence); err != nil {
+			printWarning("Warning: %v\n", err)
+		}
+	}
+}
@@ -31,6 +31,11 @@ func RunSpeedTest() (*SpeedTestResult, *NetworkQuality, error) {
 	fmt.Println()
 	printTitle("%s Network Speed Test\n", iconNetwork(""))
 
+	if options.Mock {
+		result, quality := mockSpeedTest()
+		return result, quality, nil
+	}
+
 	// Fetch server list
 	var servers speedtest.Servers
 	err := WithSpinner("Fetching server list...", func() error {
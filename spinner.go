@@ -8,6 +8,10 @@ import (
 	"time"
 )
 
+// interruptsHandled is set by commands that catch Ctrl-C themselves, so a
+// spinner leaves the signal to them instead of exiting.
+var interruptsHandled bool
+
 type Spinner struct {
 	frames []string
 	pos    int
@@ -25,18 +29,26 @@ func NewSpinner() *Spinner {
 }
 
 func (s *Spinner) Start(message string) {
-	// Handle interrupt signals to clean up spinner
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// Handle interrupt signals to clean up spinner, unless the command
+	// does; a nil channel never receives
+	var sigChan chan os.Signal
+	if !interruptsHandled {
+		sigChan = make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	}
 
 	go func() {
 		for {
 			select {
 			case <-s.stop:
+				if sigChan != nil {
+					signal.Stop(sigChan)
+				}
 				s.done <- true
 				return
 			case <-sigChan:
-				s.Stop()
+				// Stop would wait on this goroutine, so clear the line here
+				clearLine()
 				os.Exit(1)
 			default:
 				fmt.Printf("\r%s %s", s.frames[s.pos], message)
@@ -50,7 +62,7 @@ func (s *Spinner) Start(message string) {
 func (s *Spinner) Stop() {
 	s.stop <- true
 	<-s.done
-	fmt.Print("\r\033[K") // Clear the line
+	clearLine()
 }
 
 func (s *Spinner) UpdateMessage(message string) {
@@ -59,6 +71,11 @@ func (s *Spinner) UpdateMessage(message string) {
 
 // WithSpinner executes a function while showing a loading spinner
 func WithSpinner(message string, fn func() error) error {
+	// Screen readers announce every frame, so plain output skips it, and
+	// JSON and NDJSON output must stay machine readable
+	if options.Plain || options.NDJSON || options.JSON {
+		return fn()
+	}
 	spinner := NewSpinner()
 	spinner.Start(message)
 	
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const subscriptionsFile = "subscriptions.json"
+
+// renewalWarningDays is how far ahead upcoming renewals are highlighted.
+const renewalWarningDays = 7
+
+// Subscription is a recurring payment tracked by `nomad subs`.
+type Subscription struct {
+	Name     string  `json:"name"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	Cycle    string  `json:"cycle"` // weekly, monthly or yearly
+	// Start anchors the billing cycle; renewals fall on Start plus whole
+	// cycles
+	Start string `json:"start"`
+}
+
+// cyclesPerYear maps billing cycles to how often they renew in a year.
+var cyclesPerYear = map[string]float64{
+	"weekly":  52,
+	"monthly": 12,
+	"yearly":  1,
+}
+
+// MonthlyAmount returns the subscription cost spread over a month.
+func (s Subscription) MonthlyAmount() float64 {
+	return s.Amount * cyclesPerYear[s.Cycle] / 12
+}
+
+// NextRenewal returns the first renewal date on or after today.
+func (s Subscription) NextRenewal(today time.Time) (time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02", s.Start, today.Location())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	today = startOfDay(today)
+	next := start
+	for i := 1; next.Before(today); i++ {
+		switch s.Cycle {
+		case "weekly":
+			next = start.AddDate(0, 0, 7*i)
+		case "monthly":
+			next = start.AddDate(0, i, 0)
+		default:
+			next = start.AddDate(i, 0, 0)
+		}
+	}
+	return next, nil
+}
+
+// startOfDay returns midnight at the start of t's day in its location.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func loadSubscriptions() []Subscription {
+	var subs []Subscription
+	if err := loadJSON(subscriptionsFile, &subs); err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return subs
+}
+
+func saveSubscriptions(subs []Subscription) {
+	if err := saveJSON(subscriptionsFile, subs); err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// handleSubscriptions implements `nomad subs [add|rm]`.
+func handleSubscriptions(args []string) {
+	if len(args) == 0 || args[0] == "list" || args[0] == "ls" {
+		listSubscriptions()
+		return
+	}
+
+	switch args[0] {
+	case "add":
+		addSubscription(args[1:])
+	case "rm", "remove":
+		removeSubscription(args[1:])
+	default:
+		printError("Unknown subs command: %s\n", args[0])
+		printSubscriptionsUsage()
+		os.Exit(1)
+	}
+}
+
+func printSubscriptionsUsage() {
+	printInfo("Usage:\n")
+	fmt.Println("  nomad subs                                   List subscriptions in your home currency")
+	fmt.Println("  nomad subs add <name> <amount> <currency> <weekly|monthly|yearly> [--since YYYY-MM-DD]")
+	fmt.Println("  nomad subs rm <name>")
+	printInfo("Example: nomad subs add netflix 16.99 usd monthly\n")
+}
+
+func addSubscription(args []string) {
+	args, since, _ := popFlagValue(args, "--since")
+	if len(args) < 4 {
+		printSubscriptionsUsage()
+		os.Exit(1)
+	}
+
+	amount, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || amount <= 0 {
+		printError("Error: Invalid amount '%s'\n", args[1])
+		os.Exit(1)
+	}
+
+	currency := strings.ToUpper(args[2])
+	if len(currency) != 3 {
+		printError("Error: Currency codes must be 3 letters (e.g., USD, EUR, THB, AUD)\n")
+		os.Exit(1)
+	}
+
+	cycle := strings.ToLower(args[3])
+	if cycle == "annual" || cycle == "annually" {
+		cycle = "yearly"
+	}
+	if _, ok := cyclesPerYear[cycle]; !ok {
+		printError("Error: Unknown billing cycle '%s' (use weekly, monthly or yearly)\n", args[3])
+		os.Exit(1)
+	}
+
+	if since == "" {
+		since = time.Now().Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", since); err != nil {
+		printError("Error: Invalid date '%s' (use YYYY-MM-DD)\n", since)
+		os.Exit(1)
+	}
+
+	sub := Subscription{Name: args[0], Amount: amount, Currency: currency, Cycle: cycle, Start: since}
+
+	subs := loadSubscriptions()
+	for i := range subs {
+		if strings.EqualFold(subs[i].Name, sub.Name) {
+			subs[i] = sub
+			saveSubscriptions(subs)
+			printSuccess("Updated %s: %.2f %s %s\n", sub.Name, amount, currency, cycle)
+			return
+		}
+	}
+
+	saveSubscriptions(append(subs, sub))
+	printSuccess("Added %s: %.2f %s %s\n", sub.Name, amount, currency, cycle)
+}
+
+func removeSubscription(args []string) {
+	if len(args) < 1 {
+		printSubscriptionsUsage()
+		os.Exit(1)
+	}
+
+	subs := loadSubscriptions()
+	for i := range subs {
+		if strings.EqualFold(subs[i].Name, args[0]) {
+			name := subs[i].Name
+			saveSubscriptions(append(subs[:i], subs[i+1:]...))
+			printSuccess("Removed %s\n", name)
+			return
+		}
+	}
+
+	printError("Error: No subscription named '%s'\n", args[0])
+	os.Exit(1)
+}
+
+func listSubscriptions() {
+	subs := loadSubscriptions()
+	if len(subs) == 0 {
+		printInfo("No subscriptions yet.\n")
+		printInfo("Example: nomad subs add netflix 16.99 usd monthly\n")
+		return
+	}
+
+	home := strings.ToUpper(config.Get("home_currency"))
+	if home == "" {
+		home = "USD"
+	}
+
+	// Rates against the home currency convert every subscription at once
+	snapshotFallback = true
+	var rates *ExchangeRateResponse
+	err := WithSpinner("Fetching exchange rates...", func() error {
+		var fetchErr error
+		rates, fetchErr = getExchangeRates(home)
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error getting exchange rate: %v\n", err)
+		os.Exit(1)
+	}
+
+	today := time.Now()
+	sort.Slice(subs, func(i, j int) bool {
+		a, _ := subs[i].NextRenewal(today)
+		b, _ := subs[j].NextRenewal(today)
+		return a.Before(b)
+	})
+
+	fmt.Println()
+	printTitle("%s Subscriptions\n", iconCurrency(""))
+
+	table := NewTable("Name", "Price", "Per month ("+home+")", "Next renewal").SetTruncate(0, 24)
+	var monthlyTotal float64
+	var upcoming []string
+	for _, sub := range subs {
+		monthly := "n/a"
+		if rate, ok := rates.Rates[sub.Currency]; ok && rate > 0 {
+			converted := sub.MonthlyAmount() / rate
+			monthlyTotal += converted
+			monthly = fmt.Sprintf("%.2f", converted)
+		}
+
+		renewal := "invalid start date"
+		if next, err := sub.NextRenewal(today); err == nil {
+			days := int(next.Sub(startOfDay(today)).Hours() / 24)
+			renewal = next.Format("Mon, Jan 2")
+			if days <= renewalWarningDays {
+				renewal = colorYellow(renewal)
+				upcoming = append(upcoming, fmt.Sprintf("%s renews %s (%.2f %s)", sub.Name, next.Format("Mon, Jan 2"), sub.Amount, sub.Currency))
+			}
+		}
+
+		table.AddRow(sub.Name, fmt.Sprintf("%.2f %s %s", sub.Amount, sub.Currency, sub.Cycle), colorGreen(monthly), renewal)
+	}
+	table.Print()
+
+	fmt.Println()
+	fmt.Printf("  %s %s per month · %s per year\n", padRight(iconSuccess("Total"), 14),
+		colorGreen(fmt.Sprintf("%.2f %s", monthlyTotal, home)), colorGreen(fmt.Sprintf("%.2f %s", monthlyTotal*12, home)))
+
+	for _, line := range upcoming {
+		printWarning("  ⏰ %s\n", line)
+	}
+	printSnapshotNotice()
+	if config.Get("home_currency") == "" {
+		printInfo("\nTip: set home_currency in your config to total in your own currency\n")
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// Sun elevations, in degrees, that mark the events `nomad sun` shows.
+const (
+	// The sun's disc clears the horizon, allowing for refraction
+	sunriseElevation = -0.833
+	// Civil twilight: light enough to be outside without lamps
+	civilTwilightElevation = -6
+	// The soft, warm light photographers want lies below this
+	goldenHourElevation = 6
+)
+
+// SunDay is the sun's timetable for one day at one place, in its time
+// zone. Zero times mean the event doesn't happen that day, as near the
+// poles.
+type SunDay struct {
+	Dawn, Sunrise, SolarNoon, Sunset, Dusk time.Time
+	// GoldenMorningEnd and GoldenEveningStart bound the golden hours after
+	// sunrise and before sunset
+	GoldenMorningEnd, GoldenEveningStart time.Time
+	Daylight                             time.Duration
+	// PolarDay and PolarNight are set when the sun never sets or rises
+	PolarDay, PolarNight bool
+}
+
+// solarPosition returns the equation of time in minutes and the solar
+// declination in radians for a day, from NOAA's general solar position
+// approximation.
+func solarPosition(date time.Time) (float64, float64) {
+	gamma := 2 * math.Pi / 365 * float64(date.YearDay()-1)
+	eqTime := 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma))
+	decl := 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+	return eqTime, decl
+}
+
+// sunHourAngle returns the hour angle in degrees at which the sun is at
+// elevation, and false when it never gets there that day.
+func sunHourAngle(lat, decl, elevation float64) (float64, bool) {
+	latRad := lat * math.Pi / 180
+	zenith := (90 - elevation) * math.Pi / 180
+	cosHA := math.Cos(zenith)/(math.Cos(latRad)*math.Cos(decl)) - math.Tan(latRad)*math.Tan(decl)
+	if cosHA < -1 || cosHA > 1 {
+		return 0, false
+	}
+	return math.Acos(cosHA) * 180 / math.Pi, true
+}
+
+// sunTimes works out the sun's timetable for date at a point, entirely
+// offline.
+func sunTimes(date time.Time, lat, lon float64, loc *time.Location) SunDay {
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	eqTime, decl := solarPosition(midnight)
+	at := func(minutesUTC float64) time.Time {
+		return midnight.Add(time.Duration(minutesUTC * float64(time.Minute))).In(loc)
+	}
+	// event is when the sun passes elevation, before noon when rising
+	event := func(elevation float64, rising bool) time.Time {
+		ha, ok := sunHourAngle(lat, decl, elevation)
+		if !ok {
+			return time.Time{}
+		}
+		if rising {
+			return at(720 - 4*(lon+ha) - eqTime)
+		}
+		return at(720 - 4*(lon-ha) - eqTime)
+	}
+
+	day := SunDay{
+		SolarNoon:          at(720 - 4*lon - eqTime),
+		Dawn:               event(civilTwilightElevation, true),
+		Sunrise:            event(sunriseElevation, true),
+		GoldenMorningEnd:   event(goldenHourElevation, true),
+		GoldenEveningStart: event(goldenHourElevation, false),
+		Sunset:             event(sunriseElevation, false),
+		Dusk:               event(civilTwilightElevation, false),
+	}
+	if day.Sunrise.IsZero() {
+		// Whether the sun stays up or down depends on its height at noon
+		noonElevation := 90 - math.Abs(lat-decl*180/math.Pi)
+		day.PolarDay, day.PolarNight = noonElevation > 0, noonElevation <= 0
+		if day.PolarDay {
+			day.Daylight = 24 * time.Hour
+		}
+		return day
+	}
+	day.Daylight = day.Sunset.Sub(day.Sunrise)
+	return day
+}
+
+// handleSun implements `nomad sun [city] [--on date]`: sunrise, sunset,
+// golden hours, solar noon and day length.
+func handleSun(args []string) {
+	args, onDate, on := popFlagValue(args, "--on")
+	query, source := resolveLocation(args)
+
+	now := time.Now()
+	date := now
+	if on {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		var err error
+		if date, err = parseTravelDate(onDate, today); err != nil {
+			printError("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if source == locationFromIP {
+		// The geocoder can't locate the caller, so ask the weather provider
+		err := WithSpinner("Detecting location...", func() error {
+			var lookupErr error
+			query, lookupErr = hereQuery()
+			return lookupErr
+		})
+		if err != nil {
+			printError("Error: Could not detect your location: %v\n", err)
+			printInfo("Example: nomad sun Lisbon\n")
+			os.Exit(1)
+		}
+	}
+
+	var location *LocationInfo
+	err := WithSpinner("Finding location...", func() error {
+		var fetchErr error
+		location, fetchErr = geocodePlace(query)
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+	loc, err := time.LoadLocation(location.Timezone)
+	if err != nil {
+		printError("Error loading timezone: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Without --on, "today" is the date where the place is
+	if !on {
+		date = now.In(loc)
+	}
+	day := sunTimes(date, location.Lat, location.Lon, loc)
+	yesterday := sunTimes(date.AddDate(0, 0, -1), location.Lat, location.Lon, loc)
+
+	place := location.City
+	if location.Country != "" && location.Country != "Unknown" {
+		place += ", " + location.Country
+	}
+	clock := func(t time.Time) string {
+		if t.IsZero() {
+			return colorCyan("none")
+		}
+		return colorYellow(t.Format("3:04 PM"))
+	}
+
+	fmt.Println()
+	switch {
+	case day.PolarDay:
+		announceResult("The sun doesn't set in %s on %s", location.City, date.Format("January 2"))
+	case day.PolarNight:
+		announceResult("The sun doesn't rise in %s on %s", location.City, date.Format("January 2"))
+	default:
+		announceResult("Sunrise at %s and sunset at %s in %s", day.Sunrise.Format("3:04 PM"), day.Sunset.Format("3:04 PM"), location.City)
+	}
+	printTitle("%s Sun in %s, %s\n", iconWeather(""), place, date.Format("Mon, Jan 2"))
+
+	switch {
+	case day.PolarDay:
+		fmt.Printf("  %s %s\n", padRight(iconWeather("Midnight sun"), 14), colorYellow("the sun stays up all day"))
+	case day.PolarNight:
+		fmt.Printf("  %s %s\n", padRight(iconWeather("Polar night"), 14), colorYellow("the sun stays below the horizon"))
+	}
+	fmt.Printf("  %s %s\n", padRight(iconTime("Dawn"), 14), clock(day.Dawn))
+	fmt.Printf("  %s %s\n", padRight(iconTime("Sunrise"), 14), clock(day.Sunrise))
+	if !day.Sunrise.IsZero() && !day.GoldenMorningEnd.IsZero() {
+		fmt.Printf("  %s %s – %s\n", padRight(iconTime("Golden hour"), 14), clock(day.Sunrise), clock(day.GoldenMorningEnd))
+	}
+	fmt.Printf("  %s %s\n", padRight(iconTime("Solar noon"), 14), clock(day.SolarNoon))
+	if !day.Sunset.IsZero() && !day.GoldenEveningStart.IsZero() {
+		fmt.Printf("  %s %s – %s\n", padRight(iconTime("Golden hour"), 14), clock(day.GoldenEveningStart), clock(day.Sunset))
+	}
+	fmt.Printf("  %s %s\n", padRight(iconTime("Sunset"), 14), clock(day.Sunset))
+	fmt.Printf("  %s %s\n", padRight(iconTime("Dusk"), 14), clock(day.Dusk))
+
+	change := math.Round((day.Daylight - yesterday.Daylight).Minutes())
+	daylight := fmt.Sprintf("%dh %02dm", int(day.Daylight.Hours()), int(day.Daylight.Minutes())%60)
+	switch {
+	case day.PolarDay || day.PolarNight:
+	case change == 0:
+		daylight += " " + colorCyan("(about the same as the day before)")
+	default:
+		daylight += " " + colorForChange(change)(fmt.Sprintf("(%+.0fm on the day before)", change))
+	}
+	fmt.Printf("  %s %s\n", padRight(iconWeather("Daylight"), 14), colorYellow(daylight))
+
+	addSource(Source{What: "Sun times", From: "nomad's solar calculation", Confidence: confidenceHigh})
+	addSource(location.TimezoneSource)
+	printSources()
+}
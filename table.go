@@ -0,0 +1,311 @@
+package main
+
+import (
+	"strings"
+)
+
+// Table renders rows of cells as aligned columns. Column widths are measured
+// in terminal cells, so icons and colored text line up correctly.
+type Table struct {
+	headers   []string
+	rows      [][]string
+	maxWidths map[int]int
+	truncate  map[int]bool
+	borders   bool
+}
+
+// NewTable creates a table with the given column headers. Pass no headers
+// for a plain label/value layout.
+func NewTable(headers ...string) *Table {
+	return &Table{
+		headers:   headers,
+		maxWidths: make(map[int]int),
+		truncate:  make(map[int]bool),
+	}
+}
+
+// SetBorders toggles box-drawing borders around the table.
+func (t *Table) SetBorders(enabled bool) *Table {
+	t.borders = enabled
+	return t
+}
+
+// SetMaxWidth limits a column to width cells, wrapping longer content onto
+// additional lines.
+func (t *Table) SetMaxWidth(column, width int) *Table {
+	t.maxWidths[column] = width
+	return t
+}
+
+// SetTruncate limits a column to width cells, cutting longer content short
+// with an ellipsis instead of wrapping it. Use it for names where one line
+// per row matters more than the full text.
+func (t *Table) SetTruncate(column, width int) *Table {
+	t.maxWidths[column] = width
+	t.truncate[column] = true
+	return t
+}
+
+// AddRow appends a row of cells to the table.
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Render returns the formatted table.
+func (t *Table) Render() string {
+	columns := len(t.headers)
+	for _, row := range t.rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	// Wrap every cell first so the column widths reflect the wrapped lines
+	var header [][]string
+	if len(t.headers) > 0 {
+		header = t.wrapRow(t.headers, columns)
+		for i := range header {
+			for j := range header[i] {
+				if header[i][j] != "" {
+					header[i][j] = colorBold(header[i][j])
+				}
+			}
+		}
+	}
+	rows := make([][][]string, len(t.rows))
+	for i, row := range t.rows {
+		rows[i] = t.wrapRow(row, columns)
+	}
+
+	widths := make([]int, columns)
+	for _, cellLines := range append([][][]string{header}, rows...) {
+		for col, lines := range cellLines {
+			for _, line := range lines {
+				if w := displayWidth(line); w > widths[col] {
+					widths[col] = w
+				}
+			}
+		}
+	}
+
+	// Tables wider than the terminal would wrap mid-row, so each row is
+	// stacked as label/value lines instead
+	total := 2 + 2*(columns-1)
+	if t.borders {
+		total += 2 + columns + 1
+	}
+	for _, w := range widths {
+		total += w
+	}
+	if len(t.headers) > 1 && total > terminalWidth() {
+		return t.renderStacked(columns)
+	}
+
+	var b strings.Builder
+	if t.borders {
+		b.WriteString(t.borderLine("┌", "┬", "┐", widths))
+	}
+	if header != nil {
+		t.writeRow(&b, header, widths)
+		if t.borders {
+			b.WriteString(t.borderLine("├", "┼", "┤", widths))
+		}
+	}
+	for _, row := range rows {
+		t.writeRow(&b, row, widths)
+	}
+	if t.borders {
+		b.WriteString(t.borderLine("└", "┴", "┘", widths))
+	}
+
+	return b.String()
+}
+
+// renderStacked lays each row out as its first cell followed by one
+// "Header value" line per remaining column, for narrow terminals.
+func (t *Table) renderStacked(columns int) string {
+	labelWidth := 0
+	for _, header := range t.headers[1:] {
+		labelWidth = max(labelWidth, displayWidth(header))
+	}
+	valueWidth := max(10, terminalWidth()-6-labelWidth)
+
+	var b strings.Builder
+	for i, row := range t.rows {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for col := 0; col < columns && col < len(row); col++ {
+			if col == 0 {
+				b.WriteString("  " + colorBold(truncateText(row[0], terminalWidth()-2)) + "\n")
+				continue
+			}
+
+			var header string
+			if col < len(t.headers) {
+				header = t.headers[col]
+			}
+			for j, line := range wrapText(row[col], valueWidth) {
+				if j > 0 {
+					header = ""
+				}
+				b.WriteString(strings.TrimRight("    "+padRight(header, labelWidth)+"  "+line, " ") + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// Print writes the rendered table to stdout, through the pager when it is
+// taller than the terminal.
+func (t *Table) Print() {
+	Page(t.Render())
+}
+
+// wrapRow splits each cell of a row into lines that fit its column width.
+func (t *Table) wrapRow(row []string, columns int) [][]string {
+	cells := make([][]string, columns)
+	for col := 0; col < columns; col++ {
+		var cell string
+		if col < len(row) {
+			cell = row[col]
+		}
+		if t.truncate[col] {
+			cells[col] = []string{truncateText(cell, t.maxWidths[col])}
+		} else {
+			cells[col] = wrapText(cell, t.maxWidths[col])
+		}
+	}
+	return cells
+}
+
+// writeRow writes a (possibly multi-line) row.
+func (t *Table) writeRow(b *strings.Builder, cells [][]string, widths []int) {
+	height := 1
+	for _, lines := range cells {
+		if len(lines) > height {
+			height = len(lines)
+		}
+	}
+
+	for line := 0; line < height; line++ {
+		parts := make([]string, len(cells))
+		for col, lines := range cells {
+			var text string
+			if line < len(lines) {
+				text = lines[line]
+			}
+			parts[col] = padRight(text, widths[col])
+		}
+
+		if t.borders {
+			b.WriteString("  │ " + strings.Join(parts, " │ ") + " │\n")
+		} else {
+			b.WriteString(strings.TrimRight("  "+strings.Join(parts, "  "), " ") + "\n")
+		}
+	}
+}
+
+// borderLine builds a horizontal border using the given corner and
+// junction characters.
+func (t *Table) borderLine(left, middle, right string, widths []int) string {
+	segments := make([]string, len(widths))
+	for i, w := range widths {
+		segments[i] = strings.Repeat("─", w+2)
+	}
+	return "  " + left + strings.Join(segments, middle) + right + "\n"
+}
+
+// wrapText breaks text into lines of at most width display cells, splitting
+// on spaces where possible. A width of zero disables wrapping. Text wrapped
+// in a single color keeps that color on every line.
+func wrapText(text string, width int) []string {
+	if width <= 0 || displayWidth(text) <= width {
+		return []string{text}
+	}
+
+	// Peel off an enclosing color so each wrapped line can be re-colored
+	prefix := ""
+	if strings.HasPrefix(text, "\033[") && strings.HasSuffix(text, Reset) {
+		if end := strings.IndexByte(text, 'm'); end > 0 {
+			prefix = text[:end+1]
+			text = strings.TrimSuffix(text[end+1:], Reset)
+		}
+	}
+	text = stripANSI(text)
+
+	var lines []string
+	var current string
+	for _, word := range strings.Fields(text) {
+		// Hard-break words that are longer than the column
+		for displayWidth(word) > width {
+			head, tail := splitAtWidth(word, width)
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			lines = append(lines, head)
+			word = tail
+		}
+
+		switch {
+		case current == "":
+			current = word
+		case displayWidth(current)+1+displayWidth(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	if prefix != "" {
+		for i := range lines {
+			lines[i] = prefix + lines[i] + Reset
+		}
+	}
+	return lines
+}
+
+// truncateText shortens text to at most width display cells, ending it
+// with an ellipsis when anything was cut. Like wrapText, an enclosing color
+// is kept.
+func truncateText(text string, width int) string {
+	if width <= 0 || displayWidth(text) <= width {
+		return text
+	}
+
+	prefix := ""
+	if strings.HasPrefix(text, "\033[") && strings.HasSuffix(text, Reset) {
+		if end := strings.IndexByte(text, 'm'); end > 0 {
+			prefix = text[:end+1]
+			text = strings.TrimSuffix(text[end+1:], Reset)
+		}
+	}
+
+	head, _ := splitAtWidth(stripANSI(text), width-1)
+	head = strings.TrimRight(head, " ") + "…"
+	if prefix != "" {
+		return prefix + head + Reset
+	}
+	return head
+}
+
+// splitAtWidth splits s so that the first part fits within width cells.
+func splitAtWidth(s string, width int) (string, string) {
+	used := 0
+	for i, r := range s {
+		w := displayWidth(string(r))
+		if used+w > width && i > 0 {
+			return s[:i], s[i:]
+		}
+		used += w
+	}
+	return s, ""
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ansiEnabled controls whether colors and cursor control sequences are
+// emitted. It is switched off on consoles that cannot interpret them.
+var ansiEnabled = true
+
+// clearLine erases the current terminal line and returns the cursor to its
+// start.
+func clearLine() {
+	if ansiEnabled {
+		fmt.Print("\r\033[K")
+		return
+	}
+	fmt.Print("\r" + strings.Repeat(" ", 79) + "\r")
+}
+
+// terminalWidth returns the width of the terminal in columns. When output
+// is not a terminal it honors COLUMNS, then assumes 80.
+func terminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 80
+}
+
+// terminalHeight returns the height of the terminal in rows. When output
+// is not a terminal it honors LINES, then assumes 24.
+func terminalHeight() int {
+	if _, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil && height > 0 {
+		return height
+	}
+	if height, err := strconv.Atoi(os.Getenv("LINES")); err == nil && height > 0 {
+		return height
+	}
+	return 24
+}
+
+// displayWidth returns the number of terminal columns s occupies, ignoring
+// ANSI escape sequences and counting emoji and East Asian wide runes as two
+// columns.
+func displayWidth(s string) int {
+	s = stripANSI(s)
+	width := 0
+	for i, r := range s {
+		switch {
+		case r == '\uFE0F':
+			// An emoji presentation selector widens the preceding
+			// narrow symbol (e.g. "ℹ️", "☀️") to two columns
+			if prev, _ := utf8.DecodeLastRuneInString(s[:i]); prev != utf8.RuneError && !isWideRune(prev) {
+				width++
+			}
+		case r == '\u200D' || r == '\uFE0E' || unicode.Is(unicode.Mn, r):
+			// Zero-width joiners, text selectors and combining marks
+		case isWideRune(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// isWideRune reports whether r is rendered two columns wide.
+func isWideRune(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo
+		r >= 0x2E80 && r <= 0xA4CF, // CJK, Yi
+		r >= 0xAC00 && r <= 0xD7A3, // Hangul syllables
+		r >= 0xF900 && r <= 0xFAFF, // CJK compatibility ideographs
+		r >= 0xFE30 && r <= 0xFE4F, // CJK compatibility forms
+		r >= 0xFF00 && r <= 0xFF60, // Fullwidth forms
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1FAFF, // Emoji and pictographs
+		r >= 0x20000 && r <= 0x3FFFD:
+		return true
+	}
+	return strings.ContainsRune(emojiPresentation, r)
+}
+
+// emojiPresentation lists the BMP symbols terminals draw as wide emoji even
+// without a variation selector.
+const emojiPresentation = "⌚⌛⏩⏪⏫⏬⏰⏳◽◾☔☕♿⚓⚡⚪⚫⚽⚾⛄⛅⛎⛔⛪⛲⛳⛵⛺⛽✅✊✋✨❌❎❓❔❕❗➕➖➗➰➿⬛⬜⭐⭕"
+
+// stripANSI removes ANSI escape sequences from s.
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\033[") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
+			// Skip until the final byte of the CSI sequence
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7E) {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// padRight pads s with spaces to the given display width. Unlike %-Ns it
+// accounts for color codes and emoji so labelled columns line up.
+func padRight(s string, width int) string {
+	if w := displayWidth(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
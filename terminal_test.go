@@ -0,0 +1,115 @@
+package main
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ascii", "Weather", 7},
+		{"accented", "Zürich", 6},
+		{"combining mark", "Zu\u0308rich", 6},
+		{"ansi color", Green + "pass" + Reset, 4},
+		{"ansi bold and color", Bold + Cyan + "Rate" + Reset + Reset, 4},
+		{"bare escape", "\033[2K", 0},
+		{"wide emoji", "🌤", 2},
+		{"emoji with label", "📍 Lisbon", 9},
+		{"emoji presentation selector", "ℹ️", 2},
+		{"text presentation selector", "ℹ︎", 1},
+		{"selector after wide emoji", "🌤️", 2},
+		{"bmp emoji", "⚡", 2},
+		{"zwj sequence", "👩‍💻", 4},
+		{"cjk", "東京", 4},
+		{"hangul", "서울", 4},
+		{"fullwidth", "ＡＢ", 4},
+		{"colored cjk", Yellow + "北京" + Reset, 4},
+		{"mixed", Cyan + "🌡️ " + Reset + "東京 31°C", 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWidth(tt.in); got != tt.want {
+				t.Errorf("displayWidth(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{Red + "error" + Reset, "error"},
+		{"a" + Bold + "b" + Reset + "c", "abc"},
+		{"\033[38;5;208morange\033[0m", "orange"},
+		{"\r\033[K", "\r"},
+		{"unterminated \033[31", "unterminated "},
+		{"東京" + Reset, "東京"},
+	}
+	for _, tt := range tests {
+		if got := stripANSI(tt.in); got != tt.want {
+			t.Errorf("stripANSI(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{"ascii", "Temp", 8, "Temp    "},
+		{"exact", "Humidity", 8, "Humidity"},
+		{"too long", "Visibility", 8, "Visibility"},
+		{"emoji", "🌤 Sun", 8, "🌤 Sun  "},
+		{"emoji selector", "ℹ️ Info", 9, "ℹ️ Info  "},
+		{"ansi", Green + "ok" + Reset, 5, Green + "ok" + Reset + "   "},
+		{"cjk", "東京", 6, "東京  "},
+		{"zero width", "x", 0, "x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := padRight(tt.in, tt.width)
+			if got != tt.want {
+				t.Errorf("padRight(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+			}
+			if w := displayWidth(got); w < tt.width {
+				t.Errorf("padRight(%q, %d) is %d columns wide", tt.in, tt.width, w)
+			}
+		})
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{"fits", "Lisbon", 10, "Lisbon"},
+		{"exact", "Lisbon", 6, "Lisbon"},
+		{"ascii", "Chiang Mai, Thailand", 10, "Chiang Ma…"},
+		{"trailing space trimmed", "Chiang Mai, Thailand", 7, "Chiang…"},
+		{"no limit", "Chiang Mai, Thailand", 0, "Chiang Mai, Thailand"},
+		{"cjk not split", "東京都千代田区", 6, "東京…"},
+		{"emoji not split", "🌤🌤🌤🌤", 5, "🌤🌤…"},
+		{"color kept", Yellow + "Ho Chi Minh City" + Reset, 8, Yellow + "Ho Chi…" + Reset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateText(tt.in, tt.width)
+			if got != tt.want {
+				t.Errorf("truncateText(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+			}
+			if tt.width > 0 && displayWidth(got) > tt.width {
+				t.Errorf("truncateText(%q, %d) is %d columns wide", tt.in, tt.width, displayWidth(got))
+			}
+		})
+	}
+}
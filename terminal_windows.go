@@ -0,0 +1,35 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// cpUTF8 is the Windows code page identifier for UTF-8
+const cpUTF8 = 65001
+
+func init() {
+	// Windows 10 consoles only interpret ANSI escape sequences once virtual
+	// terminal processing has been switched on for the output handle.
+	// Older consoles reject the mode, so fall back to plain output.
+	ansiEnabled = enableVirtualTerminal(os.Stdout) == nil
+
+	// Emoji and box-drawing characters need the UTF-8 code page
+	windows.SetConsoleOutputCP(cpUTF8)
+}
+
+// enableVirtualTerminal turns on ANSI escape handling for the console
+// behind f.
+func enableVirtualTerminal(f *os.File) error {
+	handle := windows.Handle(f.Fd())
+
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return err
+	}
+
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultTicker is shown when no symbols are given or configured.
+const defaultTicker = "btc, eth"
+
+// Quote is the latest price of a coin or stock.
+type Quote struct {
+	Symbol   string
+	Price    float64
+	Currency string
+	// Change is the percentage move over 24 hours for coins, or since the
+	// previous close for stocks
+	Change float64
+	Err    error
+}
+
+// handleTicker implements `nomad ticker [symbols...]`, listing coins and
+// stocks with their price in the home currency. Symbols default to ticker
+// in the config.
+func handleTicker(args []string) {
+	symbols := args
+	if len(symbols) == 0 {
+		list := config.Get("ticker")
+		if list == "" {
+			list = defaultTicker
+		}
+		symbols = strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+
+	home := strings.ToUpper(config.Get("home_currency"))
+	if home == "" {
+		home = "USD"
+	}
+
+	var coins []string
+	var stocks []string
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if isCrypto(symbol) {
+			coins = append(coins, symbol)
+		} else {
+			stocks = append(stocks, symbol)
+		}
+	}
+
+	quotes := make(map[string]Quote)
+	homeRates := make(map[string]float64)
+	WithSpinner("Fetching quotes...", func() error {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		if len(coins) > 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				coinQuotes := getCoinQuotes(coins)
+				mu.Lock()
+				for _, quote := range coinQuotes {
+					quotes[quote.Symbol] = quote
+				}
+				mu.Unlock()
+			}()
+		}
+		for _, symbol := range stocks {
+			wg.Add(1)
+			go func(symbol string) {
+				defer wg.Done()
+				quote := getStockQuote(symbol)
+				mu.Lock()
+				quotes[symbol] = quote
+				mu.Unlock()
+			}(symbol)
+		}
+		wg.Wait()
+
+		// One rate per quote currency covers every symbol priced in it
+		for _, quote := range quotes {
+			if quote.Err != nil || quote.Currency == home {
+				continue
+			}
+			if _, ok := homeRates[quote.Currency]; !ok {
+				if rate, err := getExchangeRate(quote.Currency, home); err == nil {
+					homeRates[quote.Currency] = rate
+				}
+			}
+		}
+		return nil
+	})
+
+	fmt.Println()
+	printTitle("%s Ticker\n", iconCurrency(""))
+
+	table := NewTable("Symbol", "Price", "Change", "In "+home)
+	for _, symbol := range append(coins, stocks...) {
+		quote := quotes[symbol]
+		if quote.Err != nil {
+			logVerbose("%s quote failed: %v", symbol, quote.Err)
+			table.AddRow(symbol, colorRed("unavailable"), "", "")
+			continue
+		}
+
+		converted := ""
+		if quote.Currency == home {
+			converted = formatQuotePrice(quote.Price)
+		} else if rate, ok := homeRates[quote.Currency]; ok {
+			converted = formatQuotePrice(quote.Price * rate)
+		}
+		table.AddRow(symbol, formatQuotePrice(quote.Price)+" "+quote.Currency,
+			colorForChange(quote.Change)(fmt.Sprintf("%+.2f%%", quote.Change)), colorGreen(converted))
+	}
+	table.Print()
+
+	if len(args) == 0 && config.Get("ticker") == "" {
+		printInfo("\nTip: set ticker in your config to your own list, e.g. btc, eth, aapl\n")
+	}
+}
+
+// formatQuotePrice keeps cents on prices up to the thousands, where share
+// prices sit, and groups anything larger.
+func formatQuotePrice(price float64) string {
+	switch {
+	case price >= 10000:
+		return formatGrouped(price)
+	case price >= 1:
+		return fmt.Sprintf("%.2f", price)
+	default:
+		return formatRate(price)
+	}
+}
+
+// getCoinQuotes fetches the USD price and 24-hour change of coins from
+// CoinGecko in a single request.
+func getCoinQuotes(symbols []string) []Quote {
+	ids := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		ids[i] = cryptoAssets[symbol]
+	}
+	sort.Strings(ids)
+
+	quotes := make([]Quote, len(symbols))
+	var prices map[string]map[string]float64
+	err := fetchCachedJSON("ticker:coins:"+strings.Join(ids, ","),
+		fmt.Sprintf("https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=usd&include_24hr_change=true", strings.Join(ids, ",")),
+		cryptoCacheTTL, func(body []byte) error {
+			if err := json.Unmarshal(body, &prices); err != nil {
+				return fmt.Errorf("failed to parse JSON response: %v", err)
+			}
+			return nil
+		})
+
+	for i, symbol := range symbols {
+		quotes[i] = Quote{Symbol: symbol, Currency: "USD", Err: err}
+		if err != nil {
+			continue
+		}
+		price, ok := prices[cryptoAssets[symbol]]
+		if !ok || price["usd"] == 0 {
+			quotes[i].Err = fmt.Errorf("no price for %s", symbol)
+			continue
+		}
+		quotes[i].Price, quotes[i].Change = price["usd"], price["usd_24h_change"]
+	}
+	return quotes
+}
+
+// yahooChart is the part of Yahoo Finance's chart response with the latest
+// price.
+type yahooChart struct {
+	Chart struct {
+		Result []struct {
+			Meta struct {
+				Currency           string  `json:"currency"`
+				RegularMarketPrice float64 `json:"regularMarketPrice"`
+				ChartPreviousClose float64 `json:"chartPreviousClose"`
+			} `json:"meta"`
+		} `json:"result"`
+	} `json:"chart"`
+}
+
+// getStockQuote fetches a stock's latest price and move since the previous
+// close from Yahoo Finance.
+func getStockQuote(symbol string) Quote {
+	quote := Quote{Symbol: symbol}
+
+	var chart yahooChart
+	err := fetchCachedJSON("ticker:stock:"+symbol,
+		fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s?range=1d&interval=1d", symbol), cryptoCacheTTL,
+		func(body []byte) error {
+			if err := json.Unmarshal(body, &chart); err != nil {
+				return fmt.Errorf("failed to parse JSON response: %v", err)
+			}
+			if len(chart.Chart.Result) == 0 || chart.Chart.Result[0].Meta.RegularMarketPrice == 0 {
+				return fmt.Errorf("no quote for %s", symbol)
+			}
+			return nil
+		})
+	if err != nil {
+		quote.Err = err
+		return quote
+	}
+
+	meta := chart.Chart.Result[0].Meta
+	quote.Price, quote.Currency = meta.RegularMarketPrice, strings.ToUpper(meta.Currency)
+	if meta.ChartPreviousClose > 0 {
+		quote.Change = (meta.RegularMarketPrice - meta.ChartPreviousClose) / meta.ChartPreviousClose * 100
+	}
+	return quote
+}
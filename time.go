@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"os"
-	"strings"
 	"time"
 )
 
@@ -15,7 +14,33 @@ type TimezoneResponse struct {
 }
 
 func HandleTime(args []string) {
-	query := strings.Join(args, " ")
+	args, pick := popFlag(args, "--pick")
+	args, showMap := popFlag(args, "--open-map")
+	if args, all := popFlag(args, "--all"); all {
+		if len(args) > 0 {
+			printError("Error: --all shows every saved place, so it takes no location\n")
+			os.Exit(1)
+		}
+		handleTimeAll()
+		return
+	}
+	query, source := resolveLocation(args)
+
+	if pick {
+		query = mustPick(pickCity(query))
+	} else if source == locationFromIP {
+		// The geocoder can't locate the caller, so ask the weather provider
+		err := WithSpinner("Detecting location...", func() error {
+			var lookupErr error
+			query, lookupErr = hereQuery()
+			return lookupErr
+		})
+		if err != nil {
+			printError("Error: Could not detect your location: %v\n", err)
+			printInfo("Example: nomad time Tokyo\n")
+			os.Exit(1)
+		}
+	}
 
 	// Get location info using geocoding with loading spinner
 	var location *LocationInfo
@@ -38,9 +63,99 @@ func HandleTime(args []string) {
 	}
 
 	now := time.Now().In(loc)
+	announceResult("It is %s in %s", now.Format("3:04 PM on Monday, January 2"), location.City)
 
 	// Display time information with better formatting
 	fmt.Println()
 	printTitle("%s Current time in %s\n", iconTime(""), location.City)
-	fmt.Printf("  %-12s %s\n", iconTime("Time · "), colorYellow(now.Format("Mon, Jan 2, 2006 3:04 PM MST")))
+	fmt.Printf("  %s %s\n", padRight(iconTime("Time · "), 14), colorYellow(now.Format("Mon, Jan 2, 2006 3:04 PM MST")))
+	addSource(Source{What: "Location", From: "OpenStreetMap Nominatim", Confidence: confidenceHigh})
+	addSource(location.TimezoneSource)
+	printSources()
+
+	if showMap {
+		if err := openMap(location.Lat, location.Lon, location.City); err != nil {
+			printError("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// worldClockRow is one row of `nomad time --all`.
+type worldClockRow struct {
+	savedLocation
+	Location *LocationInfo
+	Zone     *time.Location
+}
+
+// handleTimeAll implements `nomad time --all`, a world clock for where you
+// are now, home_city and every saved place.
+func handleTimeAll() {
+	var rows []worldClockRow
+	for _, location := range savedLocations() {
+		rows = append(rows, worldClockRow{savedLocation: location})
+	}
+
+	// One at a time, as the geocoder asks for no more than a request a
+	// second and found places are saved to the same file
+	WithSpinner("Finding locations...", func() error {
+		for i := range rows {
+			row := &rows[i]
+			query := row.Query
+			if query == "" {
+				if query, row.Err = hereQuery(); row.Err != nil {
+					continue
+				}
+			}
+			if row.Location, row.Err = geocodePlace(query); row.Err != nil {
+				continue
+			}
+			row.Zone, row.Err = time.LoadLocation(row.Location.Timezone)
+		}
+		return nil
+	})
+
+	now := time.Now()
+	_, localOffset := now.Zone()
+	fmt.Println()
+	printTitle("%s Time at your places\n", iconTime(""))
+	table := NewTable("Place", "Location", "Time", "Difference").SetMaxWidth(1, 28)
+	for _, row := range rows {
+		if row.Err != nil {
+			logVerbose("%s time failed: %v", row.Name, row.Err)
+			table.AddRow(row.Name, row.Query, colorRed("unavailable"), "")
+			continue
+		}
+
+		there := now.In(row.Zone)
+		_, offset := there.Zone()
+		place := row.Location.City
+		if row.Location.Country != "" && row.Location.Country != "Unknown" {
+			place += ", " + row.Location.Country
+		}
+		table.AddRow(row.Name, place, colorYellow(there.Format("Mon 3:04 PM")), formatOffsetDiff(offset-localOffset))
+	}
+	table.Print()
+
+	if len(rows) == 1 {
+		fmt.Println()
+		printInfo("Tip: save places with `nomad places add <name> <location>` to see them here\n")
+	}
+}
+
+// formatOffsetDiff describes a difference between UTC offsets in seconds,
+// e.g. "+7h", "-3h 30m" or "same".
+func formatOffsetDiff(seconds int) string {
+	if seconds == 0 {
+		return "same"
+	}
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	hours, minutes := seconds/3600, seconds%3600/60
+	if minutes == 0 {
+		return fmt.Sprintf("%s%dh", sign, hours)
+	}
+	return fmt.Sprintf("%s%dh %02dm", sign, hours, minutes)
 }
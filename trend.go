@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TimeseriesResponse is the Frankfurter API's daily rates for a date range.
+type TimeseriesResponse struct {
+	Base      string                        `json:"base"`
+	StartDate string                        `json:"start_date"`
+	EndDate   string                        `json:"end_date"`
+	Rates     map[string]map[string]float64 `json:"rates"`
+}
+
+// RatePoint is the closing rate of a currency pair on one day.
+type RatePoint struct {
+	Date time.Time
+	Rate float64
+}
+
+// trendCacheTTL is how long a fetched series is reused. Frankfurter
+// publishes one rate per working day.
+const trendCacheTTL = 6 * time.Hour
+
+// handleTrend implements `nomad cv trend <from> <to> [--days N]`.
+func handleTrend(args []string) {
+	args, daysStr, _ := popFlagValue(args, "--days")
+	if len(args) < 2 {
+		printError("Usage: nomad cv trend <from_currency> <to_currency> [--days N]\n")
+		printInfo("Example: nomad cv trend usd thb --days 30\n")
+		os.Exit(1)
+	}
+	from, to := mustResolveCurrency(args[0]), mustResolveCurrency(args[1])
+
+	days := 30
+	if daysStr != "" {
+		var err error
+		if days, err = strconv.Atoi(daysStr); err != nil || days < 2 || days > 3650 {
+			printError("Error: Invalid number of days '%s' (use 2 to 3650)\n", daysStr)
+			os.Exit(1)
+		}
+	}
+
+	var points []RatePoint
+	err := WithSpinner("Fetching rate history...", func() error {
+		var fetchErr error
+		points, fetchErr = getRateHistory(from, to, days)
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error getting rate history: %v\n", err)
+		os.Exit(1)
+	}
+	if len(points) < 2 {
+		printError("Error: Not enough rate history for %s/%s\n", from, to)
+		os.Exit(1)
+	}
+
+	low, high := points[0], points[0]
+	var sum float64
+	for _, point := range points {
+		sum += point.Rate
+		if point.Rate < low.Rate {
+			low = point
+		}
+		if point.Rate > high.Rate {
+			high = point
+		}
+	}
+	first, last := points[0], points[len(points)-1]
+	change := (last.Rate - first.Rate) / first.Rate * 100
+
+	changeColor := colorGreen
+	if change < 0 {
+		changeColor = colorRed
+	}
+
+	values := make([]float64, len(points))
+	for i, point := range points {
+		values[i] = point.Rate
+	}
+
+	fmt.Println()
+	printTitle("%s %s → %s, last %d days\n", iconCurrency(""), from, to, days)
+	fmt.Print(NewChart(values).SetHeight(chartHeight).SetLabels(first.Date.Format("Jan 2"), last.Date.Format("Jan 2")).Render())
+	fmt.Println()
+	fmt.Printf("  %s %s %s\n", padRight(iconInfo("Low"), 14), formatRate(low.Rate), colorCyan(low.Date.Format("Mon, Jan 2")))
+	fmt.Printf("  %s %s %s\n", padRight(iconInfo("High"), 14), formatRate(high.Rate), colorCyan(high.Date.Format("Mon, Jan 2")))
+	fmt.Printf("  %s %s\n", padRight(iconInfo("Average"), 14), formatRate(sum/float64(len(points))))
+	fmt.Printf("  %s %s %s\n", padRight(iconSuccess("Latest"), 14), colorYellow(formatRate(last.Rate)),
+		changeColor(fmt.Sprintf("(%+.2f%% over %d days)", change, days)))
+}
+
+// getRateHistory returns the daily rates of from/to over the last days,
+// oldest first. Weekends and holidays have no rate.
+func getRateHistory(from, to string, days int) ([]RatePoint, error) {
+	end := time.Now()
+	start := end.AddDate(0, 0, -days)
+	key := fmt.Sprintf("trend:%s:%s:%s:%s", from, to, start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	var response TimeseriesResponse
+	err := fetchCachedJSON(key, fmt.Sprintf("https://api.frankfurter.app/%s..%s?from=%s&to=%s",
+		start.Format("2006-01-02"), end.Format("2006-01-02"), from, to), trendCacheTTL,
+		func(body []byte) error {
+			if err := json.Unmarshal(body, &response); err != nil {
+				return schemaMismatch("frankfurter", "invalid JSON: "+err.Error(), body)
+			}
+			if response.Rates == nil {
+				return schemaMismatch("frankfurter", "no rates", body)
+			}
+			return nil
+		})
+	var statusErr *statusError
+	if errors.As(err, &statusErr) &&
+		(statusErr.Status == http.StatusNotFound || statusErr.Status == http.StatusUnprocessableEntity) {
+		return nil, fmt.Errorf("no history for %s/%s (only major currencies are covered)", from, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var points []RatePoint
+	for day, rates := range response.Rates {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			continue
+		}
+		if rate, ok := rates[to]; ok {
+			points = append(points, RatePoint{Date: date, Rate: rate})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Date.Before(points[j].Date)
+	})
+
+	return points, nil
+}
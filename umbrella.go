@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// umbrellaHours is how far ahead `nomad umbrella` looks.
+const umbrellaHours = 12
+
+// umbrellaChance is the chance of rain, in percent, from which an umbrella
+// is worth carrying.
+const umbrellaChance = 50
+
+// RainSlot is one three-hour slot of wttr.in's forecast, in local time.
+type RainSlot struct {
+	Start    time.Time
+	Chance   int
+	PrecipMM float64
+}
+
+// rainSlots returns the forecast slots overlapping the hours after now.
+func rainSlots(response *WttrResponse, now time.Time, hours int) []RainSlot {
+	end := now.Add(time.Duration(hours) * time.Hour)
+	var slots []RainSlot
+	for _, day := range response.Weather {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			continue
+		}
+		for _, entry := range day.Hourly {
+			hour, ok := entry.Hour()
+			if !ok {
+				continue
+			}
+			start := date.Add(time.Duration(hour) * time.Hour)
+			if !start.Add(3*time.Hour).After(now) || !start.Before(end) {
+				continue
+			}
+			// A slot without a chance of rain can't answer the question
+			chance, ok := entry.ChanceOfRain.Float()
+			if !ok {
+				continue
+			}
+			precip, _ := entry.PrecipMM.Float()
+			slots = append(slots, RainSlot{Start: start, Chance: int(chance), PrecipMM: precip})
+		}
+	}
+	return slots
+}
+
+// handleUmbrella implements `nomad umbrella [city]` and `nomad weather
+// --umbrella`: a one-line answer on whether rain is likely in the next
+// hours, and when.
+func handleUmbrella(args []string) {
+	query, _ := resolveLocation(args)
+
+	var response *WttrResponse
+	err := WithSpinner("Fetching weather data...", func() error {
+		var fetchErr error
+		response, fetchErr = wttr.Forecast(query)
+		return fetchErr
+	})
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Slots are in the location's time, so "now" has to be too. Forecast
+	// checked there is a current observation.
+	current, _ := response.Current()
+	now, err := current.LocalTime()
+	if err != nil {
+		printError("Error: No local time in the weather data\n")
+		os.Exit(1)
+	}
+
+	slots := rainSlots(response, now, umbrellaHours)
+	if len(slots) == 0 {
+		printError("Error: No hourly forecast for the next %d hours\n", umbrellaHours)
+		os.Exit(1)
+	}
+
+	var wet *RainSlot
+	peak := slots[0]
+	for i, slot := range slots {
+		if slot.Chance > peak.Chance {
+			peak = slot
+		}
+		if wet == nil && (slot.Chance >= umbrellaChance || slot.PrecipMM >= 1) {
+			wet = &slots[i]
+		}
+	}
+
+	fmt.Println()
+	if wet == nil {
+		announceResult("No umbrella needed")
+		fmt.Printf("%s %s %s\n", iconSuccess(""), colorGreen("No umbrella needed."),
+			fmt.Sprintf("Rain chance peaks at %d%% over the next %d hours", peak.Chance, umbrellaHours))
+		return
+	}
+
+	when := "from " + wet.Start.Format("3 PM")
+	switch {
+	case !wet.Start.After(now):
+		when = "now"
+	case wet.Start.Day() != now.Day():
+		when = "from " + wet.Start.Format("3 PM") + " tomorrow"
+	}
+	announceResult("Yes, take an umbrella. Rain likely %s", when)
+	fmt.Printf("%s %s %s\n", iconWeather(""), colorYellow("Yes, take an umbrella."),
+		fmt.Sprintf("Rain likely %s (%d%% chance, %.1f mm)", when, wet.Chance, wet.PrecipMM))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Unit systems for --units and the units config key.
+const (
+	unitsMetric   = "metric"
+	unitsImperial = "imperial"
+)
+
+// imperial reports whether temperatures, speeds and distances should be
+// shown in °F, mph and miles.
+func imperial() bool {
+	return options.Units == unitsImperial
+}
+
+// tempValue converts a temperature to the chosen units.
+func tempValue(celsius float64) float64 {
+	if imperial() {
+		return celsius*9/5 + 32
+	}
+	return celsius
+}
+
+// formatTemp shows a temperature in the chosen units, e.g. "31°C" or
+// "88°F".
+func formatTemp(celsius float64) string {
+	if imperial() {
+		return fmt.Sprintf("%.0f°F", tempValue(celsius))
+	}
+	return fmt.Sprintf("%.0f°C", celsius)
+}
+
+// formatKmh shows a speed given in km/h, or in mph with imperial units.
+func formatKmh(kmh float64) string {
+	if imperial() {
+		return fmt.Sprintf("%.0f mph", kmh/1.609344)
+	}
+	return fmt.Sprintf("%.0f km/h", kmh)
+}
+
+// formatDistance shows a distance in km or miles.
+func formatDistance(km float64) string {
+	if imperial() {
+		return fmt.Sprintf("%.0f mi", km/1.609344)
+	}
+	return fmt.Sprintf("%.0f km", km)
+}
+
+// formatCm shows a snow depth or snowfall in cm, or inches with imperial
+// units.
+func formatCm(cm float64) string {
+	if imperial() {
+		return fmt.Sprintf("%.0f in", cm/2.54)
+	}
+	return fmt.Sprintf("%.0f cm", cm)
+}
+
+// formatMeters shows an altitude in metres, or feet with imperial units.
+func formatMeters(m float64) string {
+	unit := "m"
+	if imperial() {
+		m, unit = m/0.3048, "ft"
+	}
+	if m = math.Round(m); m < 100 {
+		return fmt.Sprintf("%.0f %s", m, unit)
+	}
+	return formatGrouped(m) + " " + unit
+}
+
+// formatRainfall shows an amount of rain in mm, or inches with imperial
+// units.
+func formatRainfall(mm float64) string {
+	if imperial() {
+		return fmt.Sprintf("%.1f in", mm/25.4)
+	}
+	return fmt.Sprintf("%.0f mm", mm)
+}
+
+// formatPressure shows air pressure in hPa, or inches of mercury with
+// imperial units.
+func formatPressure(hPa float64) string {
+	if imperial() {
+		return fmt.Sprintf("%.2f inHg", hPa*0.02953)
+	}
+	return fmt.Sprintf("%.0f hPa", hPa)
+}
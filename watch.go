@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// minWatchInterval keeps watch from hammering free rate APIs.
+const minWatchInterval = time.Minute
+
+// handleWatch implements `nomad cv watch <from> <to> [--above X] [--below Y]
+// [--interval 15m] [--notify]`, polling a rate until interrupted and
+// alerting whenever it crosses a threshold.
+func handleWatch(args []string) {
+	args, aboveStr, aboveSet := popFlagValue(args, "--above")
+	args, belowStr, belowSet := popFlagValue(args, "--below")
+	args, intervalStr, _ := popFlagValue(args, "--interval")
+	args, notify := popFlag(args, "--notify")
+
+	if len(args) < 2 {
+		printError("Usage: nomad cv watch <from_currency> <to_currency> [--above RATE] [--below RATE] [--interval 15m] [--notify]\n")
+		printInfo("Example: nomad cv watch usd thb --above 36.5 --interval 15m\n")
+		os.Exit(1)
+	}
+	from, to := mustResolveCurrency(args[0]), mustResolveCurrency(args[1])
+	if !isCurrencyCode(from) || !isCurrencyCode(to) {
+		printError("Error: Currency codes must be 3 letters (e.g., USD, EUR, THB, AUD) or a supported crypto (e.g., BTC, ETH, USDT)\n")
+		os.Exit(1)
+	}
+
+	var above, below float64
+	for _, threshold := range []struct {
+		set   bool
+		text  string
+		value *float64
+	}{{aboveSet, aboveStr, &above}, {belowSet, belowStr, &below}} {
+		if !threshold.set {
+			continue
+		}
+		value, err := strconv.ParseFloat(threshold.text, 64)
+		if err != nil || value <= 0 {
+			printError("Error: Invalid rate '%s'\n", threshold.text)
+			os.Exit(1)
+		}
+		*threshold.value = value
+	}
+
+	interval := 15 * time.Minute
+	if intervalStr != "" {
+		var err error
+		if interval, err = time.ParseDuration(intervalStr); err != nil || interval < minWatchInterval {
+			printError("Error: Invalid interval '%s' (use a duration of at least 1m, e.g. 15m or 1h)\n", intervalStr)
+			os.Exit(1)
+		}
+	}
+
+	// Every check must see a fresh rate, not the cached one
+	cacheRefresh = true
+
+	if !options.NDJSON {
+		fmt.Println()
+		printTitle("%s Watching %s → %s every %s\n", iconCurrency(""), from, to, formatTTL(interval))
+		if aboveSet {
+			fmt.Printf("  %s %s\n", padRight(iconInfo("Above"), 14), formatRate(above))
+		}
+		if belowSet {
+			fmt.Printf("  %s %s\n", padRight(iconInfo("Below"), 14), formatRate(below))
+		}
+		printInfo("Press Ctrl-C to stop\n\n")
+	}
+
+	// Stop cleanly, after the check in progress when there is one; the
+	// spinner leaves Ctrl-C to this channel
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+	interruptsHandled = true
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous float64
+	wasAbove, wasBelow := false, false
+	checks := 0
+	for {
+		var rate float64
+		err := WithSpinner("Fetching exchange rates...", func() error {
+			var fetchErr error
+			rate, fetchErr = getExchangeRate(from, to)
+			return fetchErr
+		})
+		checks++
+
+		now := time.Now().Format("15:04")
+		switch {
+		case err != nil && options.NDJSON:
+			emitEvent("error", map[string]interface{}{"from": from, "to": to, "error": err.Error()})
+		case err != nil:
+			printWarning("  %s  %v\n", now, err)
+		case options.NDJSON:
+			fields := map[string]interface{}{"from": from, "to": to, "rate": rate}
+			if previous > 0 {
+				fields["change_pct"] = (rate - previous) / previous * 100
+			}
+			emitEvent("rate", fields)
+		default:
+			change := ""
+			if previous > 0 {
+				delta := (rate - previous) / previous * 100
+				switch {
+				case delta > 0:
+					change = colorGreen(fmt.Sprintf("▲ %+.2f%%", delta))
+				case delta < 0:
+					change = colorRed(fmt.Sprintf("▼ %+.2f%%", delta))
+				default:
+					change = "="
+				}
+			}
+			fmt.Printf("  %s  %s  %s\n", colorCyan(now), colorYellow(formatRate(rate)), change)
+		}
+
+		if err == nil {
+			previous = rate
+
+			// Alert once per crossing; the alert re-arms when the rate
+			// comes back
+			isAbove, isBelow := aboveSet && rate > above, belowSet && rate < below
+			if isAbove && !wasAbove {
+				alertRate(from, to, rate, "above", above, notify)
+			}
+			if isBelow && !wasBelow {
+				alertRate(from, to, rate, "below", below, notify)
+			}
+			wasAbove, wasBelow = isAbove, isBelow
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			if options.NDJSON {
+				emitEvent("stop", map[string]interface{}{"checks": checks})
+				return
+			}
+			fmt.Println()
+			printInfo("Stopped after %d check(s)\n", checks)
+			return
+		}
+	}
+}
+
+// alertRate rings the terminal bell and reports a threshold crossing, on
+// the desktop too when asked.
+func alertRate(from, to string, rate float64, direction string, threshold float64, desktop bool) {
+	message := fmt.Sprintf("%s/%s is %s, %s %s", from, to, formatRate(rate), direction, formatRate(threshold))
+	if options.NDJSON {
+		emitEvent("alert", map[string]interface{}{
+			"from": from, "to": to, "rate": rate, "direction": direction, "threshold": threshold,
+		})
+	} else {
+		fmt.Print("\a")
+		printSuccess("  %s %s\n", iconSuccess(""), message)
+	}
+
+	if desktop {
+		if err := Notify("nomad rate alert", message); err != nil {
+			printWarning("  Warning: %v\n", err)
+		}
+	}
+	if options.Speak {
+		if err := Speak(message); err != nil {
+			printWarning("  Warning: %v\n", err)
+		}
+	}
+}
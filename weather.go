@@ -1,189 +1,168 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"os"
 	"strings"
-	"time"
 )
 
-type WeatherResponse struct {
-	Main struct {
-		Temp     float64 `json:"temp"`
-		Humidity int     `json:"humidity"`
-		Pressure int     `json:"pressure"`
-	} `json:"main"`
-	Weather []struct {
-		Description string `json:"description"`
-		Main        string `json:"main"`
-	} `json:"weather"`
-	Wind struct {
-		Speed float64 `json:"speed"`
-	} `json:"wind"`
-	Name string `json:"name"`
-}
-
 func HandleWeather(args []string) {
-	query := strings.Join(args, " ")
-
-	// Fetch weather data with loading spinner
-	var weatherData map[string]interface{}
-	err := WithSpinner("Fetching weather data...", func() error {
-		// Using wttr.in - if no query provided, it will auto-detect location based on IP
-		var apiURL string
-		if query == "" {
-			apiURL = "https://wttr.in/?format=j1"
-		} else {
-			// URL encode the query to handle spaces and special characters
-			encodedQuery := url.QueryEscape(query)
-			apiURL = fmt.Sprintf("https://wttr.in/%s?format=j1", encodedQuery)
-		}
-
-		client := &http.Client{
-			Timeout: 30 * time.Second,
-		}
-
-		resp, err := client.Get(apiURL)
-		if err != nil {
-			return fmt.Errorf("error fetching weather data: %v", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("weather API returned status code %d", resp.StatusCode)
+	if len(args) > 0 && args[0] == "rank" {
+		handleWeatherRank(args[1:])
+		return
+	}
+	args, pick := popFlag(args, "--pick")
+	args, showMap := popFlag(args, "--open-map")
+	args, cardPath, _ := popFlagValue(args, "--card")
+	args, snow := popFlag(args, "--snow")
+	args, art := popFlag(args, "--art")
+	args, detail := popFlag(args, "--detail")
+	args, onDate, on := popFlagValue(args, "--on")
+	args, climate := popFlag(args, "--climate")
+	args, format, formatSet := popFlagValue(args, "--format")
+	if args, umbrella := popFlag(args, "--umbrella"); umbrella {
+		handleUmbrella(args)
+		return
+	}
+	if args, all := popFlag(args, "--all"); all {
+		if len(args) > 0 {
+			printError("Error: --all shows every saved place, so it takes no location\n")
+			os.Exit(1)
 		}
+		handleWeatherAll()
+		return
+	}
+	query, source := resolveLocation(args)
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("error reading response: %v", err)
-		}
+	// Without a city the IP-based location is used, unless a pick is requested
+	if pick {
+		query = mustPick(pickCity(strings.Join(args, " ")))
+		source = locationFromArgs
+	}
 
-		// Parse the JSON response from wttr.in
-		if err := json.Unmarshal(body, &weatherData); err != nil {
-			return fmt.Errorf("error parsing weather data: %v", err)
-		}
+	if on {
+		handleWeatherOn(query, onDate)
+		return
+	}
+	if climate {
+		handleWeatherClimate(query)
+		return
+	}
+	if formatSet {
+		handleWeatherFormat(query, format)
+		return
+	}
 
-		return nil
+	// Fetch weather data with loading spinner
+	var weather *Weather
+	err := WithSpinner("Fetching weather data...", func() error {
+		var fetchErr error
+		weather, fetchErr = currentWeather(query)
+		return fetchErr
 	})
 
 	if err != nil {
 		printError("Error: %v\n", err)
 		os.Exit(1)
 	}
+	logVerbose("weather from %s", weather.Provider)
 
-	// Extract current weather information safely
-	currentConditions, ok := weatherData["current_condition"].([]interface{})
-	if !ok || len(currentConditions) == 0 {
-		printError("Error: Unable to parse weather data\n")
-		os.Exit(1)
-	}
-
-	current, ok := currentConditions[0].(map[string]interface{})
-	if !ok {
-		printError("Error: Unable to parse current weather conditions\n")
-		os.Exit(1)
+	// Only an IP-based lookup says where the user actually is. Other
+	// providers learn it through hereQuery, which remembers it already.
+	if source == locationFromIP && weather.Provider == "wttr" {
+		rememberCountry(weather.Country)
 	}
 
 	// Display weather information with better formatting
 	fmt.Println()
 
-	// Get location name from response
-	var locationName string
-	if nearestArea, ok := weatherData["nearest_area"].([]interface{}); ok && len(nearestArea) > 0 {
-		if areaMap, ok := nearestArea[0].(map[string]interface{}); ok {
-			var areaName, country string
-
-			// Get area name
-			if areaNameArr, ok := areaMap["areaName"].([]interface{}); ok && len(areaNameArr) > 0 {
-				if areaNameMap, ok := areaNameArr[0].(map[string]interface{}); ok {
-					if value, ok := areaNameMap["value"].(string); ok {
-						areaName = value
-					}
-				}
-			}
-
-			// Get country
-			if countryArr, ok := areaMap["country"].([]interface{}); ok && len(countryArr) > 0 {
-				if countryMap, ok := countryArr[0].(map[string]interface{}); ok {
-					if value, ok := countryMap["value"].(string); ok {
-						country = value
-					}
-				}
-			}
-
-			// Build location name
-			if areaName != "" && country != "" {
-				locationName = fmt.Sprintf("%s, %s", areaName, country)
-			} else if areaName != "" {
-				locationName = areaName
-			} else {
-				locationName = query // fallback to query
-			}
-		}
-	} else {
+	locationName := weather.Location
+	if locationName == "" {
 		locationName = query // fallback to query
 	}
+	condition := weather.Condition
+	temp, hasTemp := weather.TempC, weather.HasTemp
+	feelsLike, hasFeelsLike := weather.FeelsLikeC, weather.HasFeelsLike
 
-	// Build the main weather line
-	var condition, tempC, feelsLikeC string
+	if condition != "" && hasTemp {
+		announceResult("%s and %.0f degrees in %s", condition, tempValue(temp), locationName)
+	}
+
+	card := NewCard("Weather in " + locationName)
+
+	// Display main weather line, or the glyph view that replaces the lines
+	// below with --art
+	if art {
+		printWeatherArt(weather)
+	}
+	if condition != "" && hasTemp {
+		switch {
+		case art:
+		case hasFeelsLike && feelsLike != temp:
+			fmt.Printf("%s %s in %s, %s (feels like %s)\n", iconWeather(""), colorCyan(condition), locationName, colorYellow(formatTemp(temp)), colorYellow(formatTemp(feelsLike)))
+		default:
+			fmt.Printf("%s %s in %s, %s\n", iconWeather(""), colorCyan(condition), locationName, colorYellow(formatTemp(temp)))
+		}
+		card.Add("Now", condition)
+		card.Add("Temperature", formatTemp(temp))
+		if hasFeelsLike {
+			card.Add("Feels like", formatTemp(feelsLike))
+		}
+	}
 
-	// Get condition
-	if weatherDesc, ok := current["weatherDesc"].([]interface{}); ok && len(weatherDesc) > 0 {
-		if descMap, ok := weatherDesc[0].(map[string]interface{}); ok {
-			if value, ok := descMap["value"].(string); ok {
-				condition = value
-			}
+	// UV Index on separate line
+	if weather.UVIndex != "" {
+		if !art {
+			fmt.Printf("%s UV Index: %s\n", iconUV(""), colorYellow(weather.UVIndex))
 		}
+		card.Add("UV index", weather.UVIndex)
 	}
 
-	// Get temperature
-	if temp, ok := current["temp_C"].(string); ok {
-		tempC = temp
+	if detail {
+		printWeatherDetail(weather)
 	}
+	printWeatherFallbackNotice()
+
+	saveCard(card, cardPath)
 
-	// Get feels like
-	if feelsLike, ok := current["FeelsLikeC"].(string); ok {
-		feelsLikeC = feelsLike
+	if snow {
+		printSnowReport(weather)
+		printSources()
 	}
 
-	// Display main weather line
-	if condition != "" && tempC != "" {
-		if feelsLikeC != "" && feelsLikeC != tempC {
-			fmt.Printf("%s %s in %s, %s°C (feels like %s°C)\n", iconWeather(""), colorCyan(condition), locationName, colorYellow(tempC), colorYellow(feelsLikeC))
-		} else {
-			fmt.Printf("%s %s in %s, %s°C\n", iconWeather(""), colorCyan(condition), locationName, colorYellow(tempC))
+	if showMap {
+		if !weather.HasCoords {
+			printError("Error: No coordinates returned for %s\n", locationName)
+			os.Exit(1)
+		}
+		if err := openMap(weather.Lat, weather.Lon, locationName); err != nil {
+			printError("Error: %v\n", err)
+			os.Exit(1)
 		}
 	}
+}
 
-	// UV Index on separate line
-	if uvIndex, ok := current["uvIndex"].(string); ok {
-		fmt.Printf("%s UV Index: %s\n", iconUV(""), colorYellow(uvIndex))
-	}
-
-	// Sunrise and Sunset
-	if weather, ok := weatherData["weather"].([]interface{}); ok && len(weather) > 0 {
-		if weatherMap, ok := weather[0].(map[string]interface{}); ok {
-			if astronomy, ok := weatherMap["astronomy"].([]interface{}); ok && len(astronomy) > 0 {
-				if astroMap, ok := astronomy[0].(map[string]interface{}); ok {
-					var sunrise, sunset string
-
-					if sunriseArr, ok := astroMap["sunrise"].(string); ok {
-						sunrise = sunriseArr
-					}
-
-					if sunsetArr, ok := astroMap["sunset"].(string); ok {
-						sunset = sunsetArr
-					}
-
-					if sunrise != "" && sunset != "" {
-						fmt.Printf("🌅 Sunrise: %s  🌇 Sunset: %s\n", colorYellow(sunrise), colorYellow(sunset))
-					}
-				}
-			}
+// printWeatherDetail adds the rest of the current conditions for
+// `nomad weather --detail`, leaving out what the provider didn't report.
+func printWeatherDetail(weather *Weather) {
+	fmt.Println()
+	if weather.WindKmh >= 0 {
+		wind := formatKmh(weather.WindKmh)
+		if weather.WindDir != "" {
+			wind += " from the " + weather.WindDir
 		}
+		fmt.Printf("  %s %s\n", padRight(iconWind("Wind"), 14), colorYellow(wind))
+	}
+	if weather.Humidity >= 0 {
+		fmt.Printf("  %s %s\n", padRight(iconHumidity("Humidity"), 14), colorYellow(fmt.Sprintf("%d%%", weather.Humidity)))
+	}
+	if weather.PressureHPa >= 0 {
+		fmt.Printf("  %s %s\n", padRight(iconInfo("Pressure"), 14), colorYellow(formatPressure(weather.PressureHPa)))
+	}
+	if weather.VisibilityKm >= 0 {
+		fmt.Printf("  %s %s\n", padRight(iconInfo("Visibility"), 14), colorYellow(formatDistance(weather.VisibilityKm)))
+	}
+	if weather.CloudCover >= 0 {
+		fmt.Printf("  %s %s\n", padRight(iconWeather("Cloud cover"), 14), colorYellow(fmt.Sprintf("%d%%", weather.CloudCover)))
 	}
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// savedLocation is one row of `nomad weather --all`.
+type savedLocation struct {
+	Name, Query string
+	Weather     *Weather
+	Err         error
+}
+
+// savedLocations returns where you are now, home_city and every place
+// saved with `nomad places add`, in that order.
+func savedLocations() []savedLocation {
+	locations := []savedLocation{{Name: "here"}}
+	if homeCity := strings.TrimSpace(config.Get("home_city")); homeCity != "" {
+		locations = append(locations, savedLocation{Name: "home", Query: homeCity})
+	}
+
+	places := loadPlaces()
+	names := make([]string, 0, len(places))
+	for name := range places {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		locations = append(locations, savedLocation{Name: name, Query: places[name]})
+	}
+	return locations
+}
+
+// handleWeatherAll implements `nomad weather --all`, the current weather at
+// every saved location side by side.
+func handleWeatherAll() {
+	if _, err := currentWeatherProvider(); err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	locations := savedLocations()
+	WithSpinner("Fetching weather data...", func() error {
+		var wg sync.WaitGroup
+		for i := range locations {
+			wg.Add(1)
+			go func(location *savedLocation) {
+				defer wg.Done()
+				location.Weather, location.Err = currentWeather(location.Query)
+			}(&locations[i])
+		}
+		wg.Wait()
+		return nil
+	})
+
+	fmt.Println()
+	printTitle("%s Weather at your places\n", iconWeather(""))
+	table := NewTable("Place", "Location", "Now", "Temp", "Feels like", "Wind").SetMaxWidth(1, 28).SetTruncate(2, 22)
+	for _, location := range locations {
+		if location.Err != nil {
+			logVerbose("%s weather failed: %v", location.Name, location.Err)
+			table.AddRow(location.Name, location.Query, colorRed("unavailable"), "", "", "")
+			continue
+		}
+
+		weather := location.Weather
+		temp, feelsLike, wind := "", "", ""
+		if weather.HasTemp {
+			temp = colorYellow(formatTemp(weather.TempC))
+		}
+		if weather.HasFeelsLike {
+			feelsLike = formatTemp(weather.FeelsLikeC)
+		}
+		if weather.WindKmh >= 0 {
+			wind = formatKmh(weather.WindKmh)
+		}
+		table.AddRow(location.Name, weather.Location, colorCyan(weather.Condition), temp, feelsLike, wind)
+	}
+	table.Print()
+	printWeatherFallbackNotice()
+
+	if len(locations) == 1 {
+		fmt.Println()
+		printInfo("Tip: save places with `nomad places add <name> <location>` to see them here\n")
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// weatherGlyph is a five-line drawing of a sky, after wttr.in's terminal
+// view.
+type weatherGlyph struct {
+	Lines [5]string
+	Color func(string) string
+}
+
+// noColor leaves a glyph in the terminal's own color.
+func noColor(text string) string { return text }
+
+var (
+	glyphSunny = weatherGlyph{[5]string{
+		`    \   /    `,
+		`     .-.     `,
+		`  - (   ) -  `,
+		"     `-'     ",
+		`    /   \    `,
+	}, colorYellow}
+	glyphPartlyCloudy = weatherGlyph{[5]string{
+		`   \  /      `,
+		` _ /"".-.    `,
+		`   \_(   ).  `,
+		`   /(___(__) `,
+		`             `,
+	}, colorYellow}
+	glyphCloudy = weatherGlyph{[5]string{
+		`             `,
+		`     .--.    `,
+		`  .-(    ).  `,
+		` (___.__)__) `,
+		`             `,
+	}, noColor}
+	glyphRain = weatherGlyph{[5]string{
+		`     .-.     `,
+		`    (   ).   `,
+		`   (___(__)  `,
+		`    ' ' ' '  `,
+		`   ' ' ' '   `,
+	}, colorCyan}
+	glyphSnow = weatherGlyph{[5]string{
+		`     .-.     `,
+		`    (   ).   `,
+		`   (___(__)  `,
+		`    *  *  *  `,
+		`   *  *  *   `,
+	}, noColor}
+	glyphThunder = weatherGlyph{[5]string{
+		`     .-.     `,
+		`    (   ).   `,
+		`   (___(__)  `,
+		`    /_ ' /_  `,
+		`     /  ' /  `,
+	}, colorYellow}
+	glyphFog = weatherGlyph{[5]string{
+		`             `,
+		` _ - _ - _ - `,
+		`  _ - _ - _  `,
+		` _ - _ - _ - `,
+		`             `,
+	}, noColor}
+	glyphUnknown = weatherGlyph{[5]string{
+		`    .-.      `,
+		`     __)     `,
+		`    (        `,
+		"     `-'     ",
+		`      .      `,
+	}, noColor}
+)
+
+// glyphFor picks the drawing for a condition description. Descriptions
+// differ between providers, so it goes by keywords, the most severe first.
+func glyphFor(condition string) weatherGlyph {
+	condition = strings.ToLower(condition)
+	has := func(words ...string) bool {
+		for _, word := range words {
+			if strings.Contains(condition, word) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case has("thunder"):
+		return glyphThunder
+	case has("snow", "sleet", "blizzard", "ice pellets"):
+		return glyphSnow
+	case has("rain", "drizzle", "shower"):
+		return glyphRain
+	case has("fog", "mist", "haze", "smoke"):
+		return glyphFog
+	case has("partly", "few clouds", "scattered"):
+		return glyphPartlyCloudy
+	case has("cloud", "overcast"):
+		return glyphCloudy
+	case has("sun", "clear"):
+		return glyphSunny
+	}
+	return glyphUnknown
+}
+
+// printWeatherArt shows the current weather as a glyph with the details
+// beside it, for `nomad weather --art`.
+func printWeatherArt(weather *Weather) {
+	fmt.Printf("%s %s\n", iconLocation(""), colorBold(weather.Location))
+	fmt.Println()
+
+	var details []string
+	details = append(details, colorCyan(weather.Condition))
+	if weather.HasTemp {
+		temp := colorYellow(formatTemp(weather.TempC))
+		if weather.HasFeelsLike && weather.FeelsLikeC != weather.TempC {
+			temp += fmt.Sprintf(" (feels like %s)", colorYellow(formatTemp(weather.FeelsLikeC)))
+		}
+		details = append(details, temp)
+	}
+	if weather.WindKmh >= 0 {
+		details = append(details, "Wind "+formatKmh(weather.WindKmh))
+	}
+	if weather.Humidity >= 0 {
+		details = append(details, fmt.Sprintf("Humidity %d%%", weather.Humidity))
+	}
+	if weather.Sunrise != "" && weather.Sunset != "" {
+		details = append(details, fmt.Sprintf("Sun %s – %s", weather.Sunrise, weather.Sunset))
+	}
+	if weather.UVIndex != "" && len(details) < 5 {
+		details = append(details, "UV index "+weather.UVIndex)
+	}
+
+	glyph := glyphFor(weather.Condition)
+	for i, line := range glyph.Lines {
+		detail := ""
+		if i < len(details) {
+			detail = details[i]
+		}
+		fmt.Printf("  %s  %s\n", glyph.Color(line), detail)
+	}
+}
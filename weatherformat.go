@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// weatherFormatTokens are the placeholders of `nomad weather --format`,
+// after wttr.in's own one-line format. Each returns "" when the provider
+// didn't report the value.
+var weatherFormatTokens = map[byte]struct {
+	Help  string
+	Value func(w *Weather) string
+}{
+	'c': {"condition", func(w *Weather) string { return w.Condition }},
+	't': {"temperature", func(w *Weather) string {
+		if !w.HasTemp {
+			return ""
+		}
+		return formatTemp(w.TempC)
+	}},
+	'f': {"feels-like temperature", func(w *Weather) string {
+		if !w.HasFeelsLike {
+			return ""
+		}
+		return formatTemp(w.FeelsLikeC)
+	}},
+	'w': {"wind speed and direction", func(w *Weather) string {
+		if w.WindKmh < 0 {
+			return ""
+		}
+		return strings.TrimSpace(w.WindDir + " " + formatKmh(w.WindKmh))
+	}},
+	'h': {"humidity", func(w *Weather) string {
+		if w.Humidity < 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d%%", w.Humidity)
+	}},
+	'P': {"pressure", func(w *Weather) string {
+		if w.PressureHPa < 0 {
+			return ""
+		}
+		return formatPressure(w.PressureHPa)
+	}},
+	'u': {"UV index", func(w *Weather) string { return w.UVIndex }},
+	'l': {"location", func(w *Weather) string { return w.Location }},
+	'S': {"sunrise", func(w *Weather) string { return w.Sunrise }},
+	's': {"sunset", func(w *Weather) string { return w.Sunset }},
+}
+
+// formatWeather fills the placeholders of format from weather, returning
+// an error for an unknown one so typos don't go unnoticed in prompts.
+func formatWeather(format string, weather *Weather) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+		if i+1 == len(format) {
+			return "", fmt.Errorf("format ends with a lone %%")
+		}
+		i++
+		if format[i] == '%' {
+			out.WriteByte('%')
+			continue
+		}
+		token, ok := weatherFormatTokens[format[i]]
+		if !ok {
+			return "", fmt.Errorf("unknown placeholder %%%c in format", format[i])
+		}
+		out.WriteString(token.Value(weather))
+	}
+	return out.String(), nil
+}
+
+// weatherFormatHelp lists the placeholders, for error messages.
+func weatherFormatHelp() string {
+	keys := make([]string, 0, len(weatherFormatTokens))
+	for key := range weatherFormatTokens {
+		keys = append(keys, string(key))
+	}
+	sort.Strings(keys)
+
+	var help []string
+	for _, key := range keys {
+		help = append(help, "%"+key+" "+weatherFormatTokens[key[0]].Help)
+	}
+	return strings.Join(help, ", ")
+}
+
+// handleWeatherFormat implements `nomad weather --format`: one line built
+// from the format, with no spinner or colors so it can go in a prompt.
+func handleWeatherFormat(query, format string) {
+	weather, err := currentWeather(query)
+	if err != nil {
+		printError("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	line, err := formatWeather(format, weather)
+	if err != nil {
+		printError("Error: %v\n", err)
+		printInfo("Placeholders: %s\n", weatherFormatHelp())
+		os.Exit(1)
+	}
+	fmt.Println(line)
+}
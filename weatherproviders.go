@@ -0,0 +1,513 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Weather is the current weather at a place, in the same shape whichever
+// provider it came from.
+type Weather struct {
+	// Provider names the source, see weatherProviders
+	Provider string
+	// Location is the place as the provider names it, e.g. "Bangkok,
+	// Thailand"
+	Location string
+	Country  string
+	// Lat and Lon are valid when HasCoords is set
+	Lat, Lon  float64
+	HasCoords bool
+	Condition string
+	// TempC and FeelsLikeC are valid when HasTemp and HasFeelsLike are set
+	TempC, FeelsLikeC     float64
+	HasTemp, HasFeelsLike bool
+	// Humidity is a percentage and WindKmh in km/h, -1 when unknown
+	Humidity int
+	WindKmh  float64
+	// WindDir is the compass point the wind blows from, e.g. "SE"
+	WindDir string
+	// PressureHPa, VisibilityKm and CloudCover (a percentage) are -1 when
+	// unknown
+	PressureHPa  float64
+	VisibilityKm float64
+	CloudCover   int
+	// UVIndex is "" when the provider doesn't report it
+	UVIndex string
+	// Sunrise and Sunset are local times like "06:58 AM", "" when unknown
+	Sunrise, Sunset string
+}
+
+// WeatherProvider is a source of current weather.
+type WeatherProvider interface {
+	// Name is the identifier used in config and with --weather-provider
+	Name() string
+	// Host is the API hostname, for health checks
+	Host() string
+	// Current returns the weather for query, or for the IP-based location
+	// when query is empty
+	Current(query string) (*Weather, error)
+}
+
+// weatherProviders lists the available weather providers by name.
+var weatherProviders = map[string]func() (WeatherProvider, error){
+	"wttr":       func() (WeatherProvider, error) { return wttrProvider{}, nil },
+	"open-meteo": func() (WeatherProvider, error) { return openMeteo{}, nil },
+	"openweathermap": func() (WeatherProvider, error) {
+		key := providerKey("openweathermap_key", "OPENWEATHERMAP_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("openweathermap needs an API key in openweathermap_key or OPENWEATHERMAP_API_KEY")
+		}
+		return openWeatherMap{apiKey: key}, nil
+	},
+}
+
+// weatherProviderAliases are the shorter names accepted for providers.
+var weatherProviderAliases = map[string]string{"wttr.in": "wttr", "owm": "openweathermap", "openmeteo": "open-meteo"}
+
+// defaultWeatherProvider needs no account.
+const defaultWeatherProvider = "wttr"
+
+// currentWeatherProvider returns the provider chosen with
+// --weather-provider or weather_provider, or the default.
+func currentWeatherProvider() (WeatherProvider, error) {
+	name := strings.ToLower(options.WeatherProvider)
+	if name == "" {
+		name = defaultWeatherProvider
+	}
+	if alias, ok := weatherProviderAliases[name]; ok {
+		name = alias
+	}
+
+	newProvider, ok := weatherProviders[name]
+	if !ok {
+		names := make([]string, 0, len(weatherProviders))
+		for name := range weatherProviders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown weather provider '%s' (use %s)", options.WeatherProvider, strings.Join(names, ", "))
+	}
+	return newProvider()
+}
+
+// weatherFallbackOrder is the order providers are tried in after the chosen
+// one fails. Providers needing a key are skipped when it isn't set.
+var weatherFallbackOrder = []string{"wttr", "open-meteo", "openweathermap"}
+
+// weatherFallback names the provider that answered in place of the chosen
+// one during this run, and weatherFallbackReason says why. The mutex covers
+// both, as `weather --all` looks up places concurrently.
+var (
+	weatherFallback       string
+	weatherFallbackReason string
+	weatherFallbackMu     sync.Mutex
+)
+
+// weatherProviderChain returns the chosen provider followed by the
+// fallbacks that can be used.
+func weatherProviderChain() ([]WeatherProvider, error) {
+	chosen, err := currentWeatherProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []WeatherProvider{chosen}
+	for _, name := range weatherFallbackOrder {
+		if name == chosen.Name() {
+			continue
+		}
+		if provider, err := weatherProviders[name](); err == nil {
+			chain = append(chain, provider)
+		}
+	}
+	return chain, nil
+}
+
+// currentWeather asks each provider in turn for the weather at query, so a
+// wttr.in outage doesn't take the weather commands down with it. The error
+// is the chosen provider's when every one fails.
+func currentWeather(query string) (*Weather, error) {
+	chain, err := weatherProviderChain()
+	if err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+	for i, provider := range chain {
+		weather, err := provider.Current(query)
+		if err == nil {
+			if i > 0 {
+				weatherFallbackMu.Lock()
+				weatherFallback = provider.Name()
+				weatherFallbackReason = fmt.Sprintf("%s failed (%v)", chain[0].Name(), firstErr)
+				weatherFallbackMu.Unlock()
+			}
+			return weather, nil
+		}
+		logVerbose("%s failed: %v", provider.Name(), err)
+		if i == 0 {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// printWeatherFallbackNotice says when the weather came from a fallback
+// provider, and why.
+func printWeatherFallbackNotice() {
+	if weatherFallbackReason != "" {
+		printWarning("  Weather from %s: %s\n", weatherFallback, weatherFallbackReason)
+	}
+}
+
+// wttrProvider uses wttr.in, which needs no key and locates the caller by
+// IP itself.
+type wttrProvider struct{}
+
+func (wttrProvider) Name() string { return "wttr" }
+func (wttrProvider) Host() string { return "wttr.in" }
+
+func (p wttrProvider) Current(query string) (*Weather, error) {
+	response, err := wttr.Forecast(query)
+	if err != nil {
+		return nil, err
+	}
+	return p.currentFrom(response, query)
+}
+
+// currentFrom reads the current weather out of a wttr.in response.
+func (wttrProvider) currentFrom(response *WttrResponse, query string) (*Weather, error) {
+	current, ok := response.Current()
+	if !ok {
+		return nil, fmt.Errorf("unable to parse weather data")
+	}
+
+	weather := &Weather{Provider: "wttr", Location: query, Humidity: -1, WindKmh: -1,
+		PressureHPa: -1, VisibilityKm: -1, CloudCover: -1}
+	if area, ok := response.Area(); ok {
+		areaName := area.AreaName.String()
+		weather.Country = area.Country.String()
+		switch {
+		case areaName != "" && weather.Country != "":
+			weather.Location = areaName + ", " + weather.Country
+		case areaName != "":
+			weather.Location = areaName
+		}
+		weather.Lat, weather.Lon, weather.HasCoords = area.Coordinates()
+	}
+
+	// The nearest area of a point out at sea or up a mountain can be far
+	// off, so the point itself leads
+	if lat, lon, ok := parseCoordinates(query); ok {
+		weather.Lat, weather.Lon, weather.HasCoords = lat, lon, true
+		near := weather.Location
+		weather.Location = formatCoordinates(lat, lon)
+		if near != "" && near != query {
+			weather.Location += " (near " + near + ")"
+		}
+	}
+
+	weather.Condition = current.WeatherDesc.String()
+	weather.TempC, weather.HasTemp = current.TempC.Float()
+	weather.FeelsLikeC, weather.HasFeelsLike = current.FeelsLikeC.Float()
+	if humidity, ok := current.Humidity.Float(); ok {
+		weather.Humidity = int(humidity)
+	}
+	if wind, ok := current.WindKmph.Float(); ok {
+		weather.WindKmh = wind
+	}
+	weather.WindDir = current.WindDir16Point
+	if pressure, ok := current.Pressure.Float(); ok {
+		weather.PressureHPa = pressure
+	}
+	if visibility, ok := current.Visibility.Float(); ok {
+		weather.VisibilityKm = visibility
+	}
+	if cloudCover, ok := current.CloudCover.Float(); ok {
+		weather.CloudCover = int(cloudCover)
+	}
+	weather.UVIndex = strings.TrimSpace(string(current.UVIndex))
+	if today, ok := response.Today(); ok {
+		weather.Sunrise, weather.Sunset = today.Sun()
+	}
+	return weather, nil
+}
+
+// WeatherResponse is OpenWeatherMap's current weather response.
+type WeatherResponse struct {
+	Coord struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"coord"`
+	Main struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Humidity  int     `json:"humidity"`
+		Pressure  int     `json:"pressure"`
+	} `json:"main"`
+	Weather []struct {
+		Description string `json:"description"`
+		Main        string `json:"main"`
+	} `json:"weather"`
+	// Visibility is in metres, at most 10 km, and sometimes left out
+	Visibility *int `json:"visibility"`
+	Wind       struct {
+		// Speed is in metres per second with units=metric
+		Speed float64 `json:"speed"`
+		// Deg is the direction the wind blows from
+		Deg float64 `json:"deg"`
+	} `json:"wind"`
+	Clouds struct {
+		// All is the cloud cover percentage
+		All int `json:"all"`
+	} `json:"clouds"`
+	Sys struct {
+		Country string `json:"country"`
+		Sunrise int64  `json:"sunrise"`
+		Sunset  int64  `json:"sunset"`
+	} `json:"sys"`
+	// Timezone is the location's offset from UTC in seconds
+	Timezone int    `json:"timezone"`
+	Name     string `json:"name"`
+}
+
+// openWeatherMap uses OpenWeatherMap's current weather API, a keyed
+// alternative for when wttr.in is slow or rate-limited.
+type openWeatherMap struct {
+	apiKey string
+}
+
+func (openWeatherMap) Name() string { return "openweathermap" }
+func (openWeatherMap) Host() string { return "api.openweathermap.org" }
+
+func (p openWeatherMap) Current(query string) (*Weather, error) {
+	// OpenWeatherMap can't locate the caller, and it stands in when wttr.in is down,
+	// so find where that is without it
+	if query == "" {
+		here, err := hereWithoutWttr()
+		if err != nil {
+			return nil, fmt.Errorf("could not detect your location: %v", err)
+		}
+		query = here
+	}
+
+	params := url.Values{"units": {"metric"}, "appid": {p.apiKey}}
+	if lat, lon, ok := parseCoordinates(query); ok {
+		params.Set("lat", fmt.Sprintf("%.4f", lat))
+		params.Set("lon", fmt.Sprintf("%.4f", lon))
+	} else {
+		params.Set("q", query)
+	}
+
+	var response WeatherResponse
+	err := cachedDecode("weather:owm:"+strings.ToLower(query), weatherCacheTTL, func() ([]byte, error) {
+		client := newHTTPClient(15 * time.Second)
+
+		resp, err := client.Get("https://api.openweathermap.org/data/2.5/weather?" + params.Encode())
+		if err != nil {
+			return nil, fmt.Errorf("error fetching weather data: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response: %v", err)
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return body, nil
+		case http.StatusNotFound:
+			return nil, fmt.Errorf("unknown location '%s'", query)
+		case http.StatusUnauthorized:
+			return nil, fmt.Errorf("openweathermap rejected the API key (new keys take a couple of hours to activate)")
+		case http.StatusTooManyRequests:
+			return nil, fmt.Errorf("openweathermap rate limit reached, try again in a minute")
+		}
+		return nil, fmt.Errorf("weather API returned status code %d", resp.StatusCode)
+	}, func(body []byte) error {
+		if err := json.Unmarshal(body, &response); err != nil {
+			return schemaMismatch("openweathermap", "invalid JSON: "+err.Error(), body)
+		}
+		if len(response.Weather) == 0 {
+			return schemaMismatch("openweathermap", "no current conditions", body)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	weather := &Weather{
+		Provider:     "openweathermap",
+		Location:     response.Name,
+		Country:      response.Sys.Country,
+		Lat:          response.Coord.Lat,
+		Lon:          response.Coord.Lon,
+		HasCoords:    true,
+		Condition:    response.Weather[0].Description,
+		TempC:        response.Main.Temp,
+		FeelsLikeC:   response.Main.FeelsLike,
+		HasTemp:      true,
+		HasFeelsLike: true,
+		Humidity:     response.Main.Humidity,
+		WindKmh:      response.Wind.Speed * 3.6,
+		WindDir:      compassPoint(response.Wind.Deg),
+		PressureHPa:  float64(response.Main.Pressure),
+		VisibilityKm: -1,
+		CloudCover:   response.Clouds.All,
+	}
+	if response.Visibility != nil {
+		weather.VisibilityKm = float64(*response.Visibility) / 1000
+	}
+	// Descriptions are lowercase ("light rain"), unlike wttr.in's
+	if weather.Condition != "" {
+		weather.Condition = strings.ToUpper(weather.Condition[:1]) + weather.Condition[1:]
+	}
+	if weather.Location == "" {
+		weather.Location = query
+	}
+	if weather.Country != "" {
+		weather.Location += ", " + weather.Country
+	}
+	if lat, lon, ok := parseCoordinates(query); ok {
+		weather.Location = formatCoordinates(lat, lon) + " (near " + weather.Location + ")"
+	}
+
+	// Sun times are UTC; the response's offset turns them into local times
+	zone := time.FixedZone("", response.Timezone)
+	if response.Sys.Sunrise > 0 && response.Sys.Sunset > 0 {
+		weather.Sunrise = time.Unix(response.Sys.Sunrise, 0).In(zone).Format("03:04 PM")
+		weather.Sunset = time.Unix(response.Sys.Sunset, 0).In(zone).Format("03:04 PM")
+	}
+	return weather, nil
+}
+
+// openMeteo uses Open-Meteo's forecast API, which needs no key. It only
+// takes coordinates, so places are geocoded first.
+type openMeteo struct{}
+
+func (openMeteo) Name() string { return "open-meteo" }
+func (openMeteo) Host() string { return "api.open-meteo.com" }
+
+// openMeteoCurrent is the part of an Open-Meteo forecast response used for
+// the current weather.
+type openMeteoCurrent struct {
+	Current struct {
+		TempC         *float64 `json:"temperature_2m"`
+		FeelsLikeC    *float64 `json:"apparent_temperature"`
+		Humidity      *float64 `json:"relative_humidity_2m"`
+		WeatherCode   *int     `json:"weather_code"`
+		WindKmh       *float64 `json:"wind_speed_10m"`
+		WindDirection *float64 `json:"wind_direction_10m"`
+		PressureHPa   *float64 `json:"surface_pressure"`
+		CloudCover    *float64 `json:"cloud_cover"`
+		// Visibility is in metres
+		Visibility *float64 `json:"visibility"`
+	} `json:"current"`
+	Daily struct {
+		// Sunrise and Sunset are local times like "2026-10-15T07:41"
+		Sunrise []string   `json:"sunrise"`
+		Sunset  []string   `json:"sunset"`
+		UVIndex []*float64 `json:"uv_index_max"`
+	} `json:"daily"`
+}
+
+func (openMeteo) Current(query string) (*Weather, error) {
+	// Open-Meteo can't locate the caller, and it stands in when wttr.in is down,
+	// so find where that is without it
+	if query == "" {
+		here, err := hereWithoutWttr()
+		if err != nil {
+			return nil, fmt.Errorf("could not detect your location: %v", err)
+		}
+		query = here
+	}
+
+	weather := &Weather{Provider: "open-meteo", Humidity: -1, WindKmh: -1, PressureHPa: -1, VisibilityKm: -1, CloudCover: -1}
+	if lat, lon, ok := parseCoordinates(query); ok {
+		weather.Lat, weather.Lon = lat, lon
+		weather.Location = formatCoordinates(lat, lon)
+	} else {
+		coords, err := geocodeAddress(query)
+		if err != nil {
+			return nil, fmt.Errorf("geocoding failed: %v", err)
+		}
+		weather.Lat, weather.Lon = coords.Lat, coords.Lon
+		weather.Location, weather.Country = coords.City, coords.Country
+		if coords.Country != "" && coords.Country != "Unknown" {
+			weather.Location += ", " + coords.Country
+		}
+	}
+	weather.HasCoords = true
+
+	var response openMeteoCurrent
+	err := fetchCachedJSON(fmt.Sprintf("weather:open-meteo:%.2f,%.2f", weather.Lat, weather.Lon),
+		fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&current=temperature_2m,apparent_temperature,relative_humidity_2m,weather_code,wind_speed_10m,wind_direction_10m,surface_pressure,cloud_cover,visibility&daily=sunrise,sunset,uv_index_max&timezone=auto&forecast_days=1",
+			weather.Lat, weather.Lon), weatherCacheTTL,
+		func(body []byte) error {
+			if err := json.Unmarshal(body, &response); err != nil {
+				return schemaMismatch("open-meteo", "invalid JSON: "+err.Error(), body)
+			}
+			if response.Current.TempC == nil {
+				return schemaMismatch("open-meteo", "no current conditions", body)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	current := response.Current
+
+	weather.TempC, weather.HasTemp = *current.TempC, true
+	if current.FeelsLikeC != nil {
+		weather.FeelsLikeC, weather.HasFeelsLike = *current.FeelsLikeC, true
+	}
+	if current.WeatherCode != nil {
+		weather.Condition = wmoConditions[*current.WeatherCode]
+	}
+	if current.Humidity != nil {
+		weather.Humidity = int(*current.Humidity)
+	}
+	if current.WindKmh != nil {
+		weather.WindKmh = *current.WindKmh
+	}
+	if current.WindDirection != nil {
+		weather.WindDir = compassPoint(*current.WindDirection)
+	}
+	if current.PressureHPa != nil {
+		weather.PressureHPa = *current.PressureHPa
+	}
+	if current.CloudCover != nil {
+		weather.CloudCover = int(*current.CloudCover)
+	}
+	if current.Visibility != nil {
+		weather.VisibilityKm = *current.Visibility / 1000
+	}
+	if len(response.Daily.UVIndex) > 0 && response.Daily.UVIndex[0] != nil {
+		weather.UVIndex = fmt.Sprintf("%.0f", *response.Daily.UVIndex[0])
+	}
+	if len(response.Daily.Sunrise) > 0 && len(response.Daily.Sunset) > 0 {
+		sunrise, err1 := time.Parse("2006-01-02T15:04", response.Daily.Sunrise[0])
+		sunset, err2 := time.Parse("2006-01-02T15:04", response.Daily.Sunset[0])
+		if err1 == nil && err2 == nil {
+			weather.Sunrise, weather.Sunset = sunrise.Format("03:04 PM"), sunset.Format("03:04 PM")
+		}
+	}
+	return weather, nil
+}
+
+// compassPoint names the 16-point compass direction of a bearing, which
+// may be negative or past 360.
+func compassPoint(degrees float64) string {
+	points := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	degrees = math.Mod(math.Mod(degrees, 360)+360, 360)
+	return points[int(math.Round(degrees/22.5))%16]
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// wttrDownTransport answers wttr.in with 503 and everything else from the
+// mock data.
+type wttrDownTransport struct{}
+
+func (wttrDownTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "wttr.in" {
+		return fixtureResponse(req, http.StatusServiceUnavailable, "text/plain", "Service Unavailable"), nil
+	}
+	return mockTransport{}.RoundTrip(req)
+}
+
+func TestWeatherHereFallsBackWithoutWttr(t *testing.T) {
+	useMock(t)
+	replayer = wttrDownTransport{}
+	t.Setenv("OPENWEATHERMAP_API_KEY", "")
+	options.WeatherProvider = "wttr"
+	t.Cleanup(func() { weatherFallback, weatherFallbackReason = "", "" })
+
+	weather, err := currentWeather("")
+	if err != nil {
+		t.Fatalf("no weather for the IP-based location with wttr.in down: %v", err)
+	}
+	if weather.Provider != "open-meteo" {
+		t.Errorf("weather from %s, want open-meteo", weather.Provider)
+	}
+	if !strings.Contains(weather.Location, "Chiang Mai") {
+		t.Errorf("weather for %q, want the IP-based location", weather.Location)
+	}
+	if !strings.Contains(weatherFallbackReason, "503") {
+		t.Errorf("fallback reason %q doesn't mention wttr.in's status", weatherFallbackReason)
+	}
+}
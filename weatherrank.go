@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// rankDays is how many days of forecast `nomad weather rank` weighs.
+const rankDays = 7
+
+// defaultComfortBand is the range of daytime highs, in °C, that counts as
+// pleasant when comfort_band isn't set.
+var defaultComfortBand = [2]float64{20, 28}
+
+// rankedPlace is one row of `nomad weather rank`.
+type rankedPlace struct {
+	savedLocation
+	Days []DayOutlook
+	// Score is 0 to 10, the higher the better
+	Score float64
+}
+
+// parseComfortBand reads a comfort_band setting such as "20-28", in the
+// configured units, and returns it in °C.
+func parseComfortBand(text string) (float64, float64, error) {
+	lowText, highText, ok := strings.Cut(strings.TrimSpace(text), "-")
+	low, lowErr := strconv.ParseFloat(strings.TrimSpace(lowText), 64)
+	high, highErr := strconv.ParseFloat(strings.TrimSpace(highText), 64)
+	if !ok || lowErr != nil || highErr != nil || low >= high {
+		return 0, 0, fmt.Errorf("comfort_band must look like 20-28")
+	}
+	if imperial() {
+		low, high = (low-32)*5/9, (high-32)*5/9
+	}
+	return low, high, nil
+}
+
+// comfortBand returns the range of daytime highs, in °C, the ranking
+// favours.
+func comfortBand() (float64, float64) {
+	text := config.Get("comfort_band")
+	if text == "" {
+		return defaultComfortBand[0], defaultComfortBand[1]
+	}
+	low, high, err := parseComfortBand(text)
+	if err != nil {
+		logVerbose("Ignoring comfort_band: %v", err)
+		return defaultComfortBand[0], defaultComfortBand[1]
+	}
+	return low, high
+}
+
+// getWeekForecast returns the Open-Meteo daily forecast for the next
+// rankDays days at a point.
+func getWeekForecast(lat, lon float64) ([]DayOutlook, error) {
+	var days []DayOutlook
+	err := fetchCachedJSON(fmt.Sprintf("weekforecast:%.2f,%.2f", lat, lon),
+		fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&daily=weather_code,temperature_2m_max,temperature_2m_min,precipitation_sum,precipitation_probability_max,sunshine_duration&timezone=auto&forecast_days=%d",
+			lat, lon, rankDays), dayForecastCacheTTL,
+		func(body []byte) error {
+			var response openMeteoDaily
+			if err := json.Unmarshal(body, &response); err != nil {
+				return schemaMismatch("api.open-meteo.com", "invalid JSON: "+err.Error(), body)
+			}
+			if days = dailyOutlooks(&response); len(days) == 0 {
+				return schemaMismatch("api.open-meteo.com", "no daily forecast", body)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// dayScore rates a day from 0 to 1. Sunshine (10 hours is plenty), dryness
+// (10 mm washes a day out) and a high inside the comfort band count
+// equally; comfort fades over 10 degrees outside the band.
+func dayScore(day DayOutlook, low, high float64) float64 {
+	sun := 0.5
+	if day.SunHours >= 0 {
+		sun = math.Min(day.SunHours/10, 1)
+	}
+	dry := 1 - math.Min(day.PrecipMM/10, 1)
+
+	return (sun + dry + band(day.MaxC, low, high, 10)) / 3
+}
+
+// weekScore rates a run of days from 0 to 10.
+func weekScore(days []DayOutlook, low, high float64) float64 {
+	if len(days) == 0 {
+		return 0
+	}
+	var total float64
+	for _, day := range days {
+		total += dayScore(day, low, high)
+	}
+	return math.Round(total/float64(len(days))*100) / 10
+}
+
+// rankSummary returns the average sunshine a day, total rain with the
+// number of rainy days, and the average high of a week.
+func rankSummary(days []DayOutlook) (string, string, string) {
+	var sunHours, precip, highs float64
+	var sunDays, rainyDays int
+	for _, day := range days {
+		if day.SunHours >= 0 {
+			sunHours += day.SunHours
+			sunDays++
+		}
+		precip += day.PrecipMM
+		if day.PrecipMM >= 1 {
+			rainyDays++
+		}
+		highs += day.MaxC
+	}
+
+	sun := ""
+	if sunDays > 0 {
+		sun = fmt.Sprintf("%.1fh", sunHours/float64(sunDays))
+	}
+	rain := "dry"
+	if rainyDays > 0 {
+		rain = fmt.Sprintf("%.0f mm, %d day", precip, rainyDays)
+		if rainyDays > 1 {
+			rain += "s"
+		}
+	}
+	return sun, rain, formatTemp(highs / float64(len(days)))
+}
+
+// handleWeatherRank implements `nomad weather rank`: the saved places
+// ordered by how good their weather looks over the next week.
+func handleWeatherRank(args []string) {
+	if len(args) > 0 {
+		printError("Error: rank compares your saved places, so it takes no location\n")
+		os.Exit(1)
+	}
+
+	low, high := comfortBand()
+	var places []rankedPlace
+	for _, location := range savedLocations() {
+		places = append(places, rankedPlace{savedLocation: location})
+	}
+
+	WithSpinner("Fetching forecasts...", func() error {
+		var wg sync.WaitGroup
+		for i := range places {
+			wg.Add(1)
+			go func(place *rankedPlace) {
+				defer wg.Done()
+				if place.Weather, place.Err = currentWeather(place.Query); place.Err != nil {
+					return
+				}
+				if !place.Weather.HasCoords {
+					place.Err = fmt.Errorf("no coordinates for %s", place.Weather.Location)
+					return
+				}
+				place.Days, place.Err = getWeekForecast(place.Weather.Lat, place.Weather.Lon)
+				place.Score = weekScore(place.Days, low, high)
+			}(&places[i])
+		}
+		wg.Wait()
+		return nil
+	})
+
+	// Best first, with places that couldn't be scored last
+	sort.SliceStable(places, func(i, j int) bool {
+		if (places[i].Err == nil) != (places[j].Err == nil) {
+			return places[i].Err == nil
+		}
+		return places[i].Score > places[j].Score
+	})
+
+	fmt.Println()
+	if best := places[0]; best.Err == nil {
+		announceResult("%s has the best weather this week, scoring %.1f out of 10", best.Weather.Location, best.Score)
+	}
+	printTitle("%s Best weather over the next %d days\n", iconWeather(""), rankDays)
+	table := NewTable("#", "Place", "Location", "Score", "Sun a day", "Rain", "Highs").SetTruncate(2, 28)
+	for i, place := range places {
+		if place.Err != nil {
+			logVerbose("%s forecast failed: %v", place.Name, place.Err)
+			table.AddRow("", place.Name, place.Query, colorRed("unavailable"), "", "", "")
+			continue
+		}
+		sun, rain, highs := rankSummary(place.Days)
+		score := fmt.Sprintf("%.1f", place.Score)
+		switch {
+		case place.Score >= 7:
+			score = colorGreen(score)
+		case place.Score < 4:
+			score = colorRed(score)
+		default:
+			score = colorYellow(score)
+		}
+		table.AddRow(strconv.Itoa(i+1), place.Name, place.Weather.Location, score, sun, rain, highs)
+	}
+	table.Print()
+	fmt.Println()
+	printInfo("Scored on sunshine, rain and highs between %s and %s (set comfort_band to change)\n",
+		formatTemp(low), formatTemp(high))
+	printWeatherFallbackNotice()
+
+	if len(places) == 1 {
+		printInfo("Tip: save places with `nomad places add <name> <location>` to rank them\n")
+	}
+	addSource(Source{What: "Forecasts", From: "Open-Meteo", Confidence: confidenceMedium,
+		Check: "the last days of the week change a lot, so rank again before you book"})
+	printSources()
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weatherCacheTTL is how long a forecast is reused.
+const weatherCacheTTL = 10 * time.Minute
+
+// wttrText is how wttr.in sends descriptions and place names: a list
+// holding a {"value": ...} object.
+type wttrText []struct {
+	Value string `json:"value"`
+}
+
+func (t wttrText) String() string {
+	if len(t) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(t[0].Value)
+}
+
+// wttrNumber is a numeric wttr.in field, which is sent as a string.
+type wttrNumber string
+
+// Float returns the number, and false when the field is missing or not a
+// number.
+func (n wttrNumber) Float() (float64, bool) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(string(n)), 64)
+	return value, err == nil
+}
+
+// WttrResponse is wttr.in's JSON forecast (format=j1).
+type WttrResponse struct {
+	CurrentCondition []WttrCurrent `json:"current_condition"`
+	NearestArea      []WttrArea    `json:"nearest_area"`
+	Weather          []WttrDay     `json:"weather"`
+}
+
+// WttrCurrent is the latest observation at the location.
+type WttrCurrent struct {
+	TempC          wttrNumber `json:"temp_C"`
+	TempF          wttrNumber `json:"temp_F"`
+	FeelsLikeC     wttrNumber `json:"FeelsLikeC"`
+	FeelsLikeF     wttrNumber `json:"FeelsLikeF"`
+	Humidity       wttrNumber `json:"humidity"`
+	WindKmph       wttrNumber `json:"windspeedKmph"`
+	WindMiles      wttrNumber `json:"windspeedMiles"`
+	WindDir16Point string     `json:"winddir16Point"`
+	Pressure       wttrNumber `json:"pressure"`
+	Visibility     wttrNumber `json:"visibility"`
+	CloudCover     wttrNumber `json:"cloudcover"`
+	UVIndex        wttrNumber `json:"uvIndex"`
+	PrecipMM       wttrNumber `json:"precipMM"`
+	WeatherDesc    wttrText   `json:"weatherDesc"`
+	// LocalObsDateTime is the observation time where the location is,
+	// e.g. "2025-01-15 02:30 PM"
+	LocalObsDateTime string `json:"localObsDateTime"`
+}
+
+// LocalTime returns when the observation was made, in the location's
+// clock time.
+func (c *WttrCurrent) LocalTime() (time.Time, error) {
+	return time.Parse("2006-01-02 03:04 PM", c.LocalObsDateTime)
+}
+
+// WttrArea is the place wttr.in matched the query to.
+type WttrArea struct {
+	AreaName  wttrText   `json:"areaName"`
+	Country   wttrText   `json:"country"`
+	Region    wttrText   `json:"region"`
+	Latitude  wttrNumber `json:"latitude"`
+	Longitude wttrNumber `json:"longitude"`
+}
+
+// Coordinates returns the area's latitude and longitude, and false when
+// either is missing.
+func (a *WttrArea) Coordinates() (float64, float64, bool) {
+	lat, latOK := a.Latitude.Float()
+	lon, lonOK := a.Longitude.Float()
+	return lat, lon, latOK && lonOK
+}
+
+// WttrDay is one day of the forecast.
+type WttrDay struct {
+	Date        string          `json:"date"`
+	MaxTempC    wttrNumber      `json:"maxtempC"`
+	MinTempC    wttrNumber      `json:"mintempC"`
+	TotalSnowCm wttrNumber      `json:"totalSnow_cm"`
+	UVIndex     wttrNumber      `json:"uvIndex"`
+	Astronomy   []WttrAstronomy `json:"astronomy"`
+	Hourly      []WttrHour      `json:"hourly"`
+}
+
+// WttrAstronomy holds a day's sun times as wttr.in formats them
+// ("06:58 AM").
+type WttrAstronomy struct {
+	Sunrise string `json:"sunrise"`
+	Sunset  string `json:"sunset"`
+}
+
+// Sun returns the day's sunrise and sunset, or empty strings when the
+// forecast has none.
+func (d *WttrDay) Sun() (string, string) {
+	if len(d.Astronomy) == 0 {
+		return "", ""
+	}
+	return d.Astronomy[0].Sunrise, d.Astronomy[0].Sunset
+}
+
+// WttrHour is one three-hourly slot of a day's forecast.
+type WttrHour struct {
+	// Time counts hours times 100 ("0", "300", ... "2100")
+	Time         wttrNumber `json:"time"`
+	TempC        wttrNumber `json:"tempC"`
+	FeelsLikeC   wttrNumber `json:"FeelsLikeC"`
+	ChanceOfRain wttrNumber `json:"chanceofrain"`
+	PrecipMM     wttrNumber `json:"precipMM"`
+	WeatherDesc  wttrText   `json:"weatherDesc"`
+}
+
+// Hour returns the hour of the day the slot starts at.
+func (h *WttrHour) Hour() (int, bool) {
+	hhmm, ok := h.Time.Float()
+	return int(hhmm) / 100, ok
+}
+
+// Current returns the latest observation.
+func (r *WttrResponse) Current() (*WttrCurrent, bool) {
+	if len(r.CurrentCondition) == 0 {
+		return nil, false
+	}
+	return &r.CurrentCondition[0], true
+}
+
+// Area returns the place the forecast is for.
+func (r *WttrResponse) Area() (*WttrArea, bool) {
+	if len(r.NearestArea) == 0 {
+		return nil, false
+	}
+	return &r.NearestArea[0], true
+}
+
+// Today returns the first day of the forecast.
+func (r *WttrResponse) Today() (*WttrDay, bool) {
+	if len(r.Weather) == 0 {
+		return nil, false
+	}
+	return &r.Weather[0], true
+}
+
+// WttrError is wttr.in turning a request down, as opposed to the network
+// failing or the response having the wrong shape.
+type WttrError struct {
+	Query  string
+	Status int
+}
+
+func (e *WttrError) Error() string {
+	switch e.Status {
+	case http.StatusNotFound:
+		return fmt.Sprintf("unknown location '%s'", e.Query)
+	case http.StatusTooManyRequests:
+		return "wttr.in is rate limiting requests; try again in a few minutes"
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Sprintf("wttr.in is overloaded (status %d); try again shortly", e.Status)
+	}
+	return fmt.Sprintf("weather API returned status code %d", e.Status)
+}
+
+// wttrClient fetches forecasts from wttr.in.
+type wttrClient struct {
+	timeout time.Duration
+}
+
+// wttr is the client the weather commands share.
+var wttr = wttrClient{timeout: 30 * time.Second}
+
+// forecastURL returns the URL of the forecast for query, which wttr.in
+// answers for the caller's IP-based location when it is empty.
+func (c wttrClient) forecastURL(query string) string {
+	if query == "" {
+		return "https://wttr.in/?format=j1"
+	}
+	// Coordinates go straight to wttr.in, which skips its geocoding
+	if lat, lon, ok := parseCoordinates(query); ok {
+		return fmt.Sprintf("https://wttr.in/%.4f,%.4f?format=j1", lat, lon)
+	}
+	return fmt.Sprintf("https://wttr.in/%s?format=j1", url.QueryEscape(query))
+}
+
+// Forecast gets the wttr.in forecast for query, or for the IP-based
+// location when query is empty.
+func (c wttrClient) Forecast(query string) (*WttrResponse, error) {
+	// Without a query wttr.in answers for the caller's IP address, which
+	// mustn't be served to someone else sharing the cache
+	cache := sharedCache()
+	if query == "" {
+		cache = privateCache()
+	}
+	var response WttrResponse
+	err := cachedDecodeIn(cache, "weather:"+strings.ToLower(query), weatherCacheTTL, func() ([]byte, error) {
+		resp, err := newHTTPClient(c.timeout).Get(c.forecastURL(query))
+		if err != nil {
+			return nil, fmt.Errorf("error fetching weather data: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			if isUnknownLocation(body) {
+				return nil, &WttrError{Query: query, Status: http.StatusNotFound}
+			}
+			return nil, &WttrError{Query: query, Status: resp.StatusCode}
+		}
+		return body, nil
+	}, func(body []byte) error {
+		if err := json.Unmarshal(body, &response); err != nil {
+			// wttr.in answers an unknown place with a line of text
+			if isUnknownLocation(body) {
+				return &WttrError{Query: query, Status: http.StatusNotFound}
+			}
+			return schemaMismatch("wttr.in", "invalid JSON: "+err.Error(), body)
+		}
+		if problem := weatherProblem(&response); problem != "" {
+			return schemaMismatch("wttr.in", problem, body)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// isUnknownLocation reports whether a wttr.in answer is its note that it
+// couldn't find the place.
+func isUnknownLocation(body []byte) bool {
+	return strings.Contains(strings.ToLower(string(body)), "unknown location")
+}